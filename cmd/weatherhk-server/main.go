@@ -1,19 +1,25 @@
 package main
 
 import (
+	"bytes"
 	"crypto/sha1"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"os"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/yookoala/weatherhk/ctxlog"
 	"github.com/yookoala/weatherhk/hkodata"
+	"github.com/yookoala/weatherhk/hkodata/glossary"
 	"github.com/yookoala/weatherhk/httpcache"
+	"go4.org/syncutil/singleflight"
 )
 
 var port int
@@ -22,6 +28,27 @@ var hostname string
 
 const noticeNonpublicAPI = "This source is not publicly announced by HKO. That means it can break without previous notice."
 const fmtRFC2612 = "Mon, 02 Jan 2006 15:04:05 GMT"
+const sourceOneJSON = "http://www.hko.gov.hk/wxinfo/json/one_json_uc.xml"
+const sourceCurrentWeather = "http://rss.weather.gov.hk/rss/CurrentWeather.xml"
+const sourceRegionJSON = "http://www.hko.gov.hk/wxinfo/json/region_json.xml"
+
+// staleWindows are the windows to serve a stale response after expired:
+// while refreshing in background, and if HKO is unavailable
+type staleWindows struct {
+	whileRevalidate time.Duration
+	ifError         time.Duration
+}
+
+// defaultStale is for data still useful for a while after expired
+// (e.g. forecasts and readings)
+var defaultStale = staleWindows{
+	whileRevalidate: time.Minute,
+	ifError:         24 * time.Hour,
+}
+
+// noStale is for data relative to the time it was generated (e.g.
+// lightning summary), which would be misleading once stale
+var noStale = staleWindows{}
 
 func init() {
 	portStr := os.Getenv("PORT")
@@ -121,6 +148,382 @@ func maxAge(expires time.Time) (maxAge int) {
 	return
 }
 
+// response is the common envelope of JSON responses
+type response struct {
+	Status   int                   `json:"status"`
+	Data     interface{}           `json:"data"`
+	Source   string                `json:"source,omitempty"`
+	Notice   string                `json:"notice,omitempty"`
+	Units    *hkodata.Units        `json:"units,omitempty"`
+	Warnings []*hkodata.FieldError `json:"warnings,omitempty"`
+}
+
+// writeError writes an error response in JSON format
+func writeError(w http.ResponseWriter, status int, err error, source string) {
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(struct {
+		Status  int    `json:"status"`
+		Message string `json:"message"`
+		Source  string `json:"source,omitempty"`
+	}{
+		Status:  status,
+		Message: err.Error(),
+		Source:  source,
+	})
+}
+
+// writeResponse writes a successful response in JSON format, with the
+// data converted to the unit system
+func writeResponse(w http.ResponseWriter, units hkodata.UnitSystem, resp response) {
+	resp.Status = http.StatusOK
+	presented := units.Units()
+	resp.Units = &presented
+	hkodata.ConvertUnits(&resp.Data, units)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// writeRawResponse writes a successful response of raw HKO data in JSON
+// format, which has no typed value to convert units of
+func writeRawResponse(w http.ResponseWriter, resp response) {
+	resp.Status = http.StatusOK
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// unitSystem reads the unit system to present data in from query
+// parameters (i.e. "units=metric", "units=imperial" or "units=si")
+func unitSystem(r *http.Request) (hkodata.UnitSystem, error) {
+	unitsStr := r.URL.Query().Get("units")
+	if unitsStr == "" {
+		return hkodata.UnitsMetric, nil
+	}
+	units, err := hkodata.ParseUnitSystem(unitsStr)
+	if err != nil {
+		return units, fmt.Errorf("invalid units: %#v (must be metric, imperial or si)", unitsStr)
+	}
+	return units, nil
+}
+
+// decodeOptions reads the decode options from query parameters
+// (i.e. "strict=true" to treat all parse warnings as fatal)
+func decodeOptions(r *http.Request) (opts []hkodata.DecodeOption, err error) {
+	strict, err := boolParam(r, "strict")
+	if strict {
+		opts = append(opts, hkodata.Strict())
+	}
+	return
+}
+
+// boolParam reads a boolean query parameter (false if empty)
+func boolParam(r *http.Request, name string) (val bool, err error) {
+	str := r.URL.Query().Get(name)
+	if str == "" {
+		return
+	}
+	if val, err = strconv.ParseBool(str); err != nil {
+		err = fmt.Errorf("invalid %s: %#v (must be true or false)", name, str)
+	}
+	return
+}
+
+// splitWarnings separates warnings of a partially decoded data from
+// the error that fails the decoding
+func splitWarnings(err error) (warnings []*hkodata.FieldError, fatal error) {
+	if parseErrors, ok := err.(hkodata.ParseError); ok && !parseErrors.Fatal() {
+		return parseErrors.Warnings(), nil
+	}
+	return nil, err
+}
+
+// upstreamError represents failure to fetch data from HKO
+type upstreamError struct {
+	err error
+}
+
+func (err upstreamError) Error() string {
+	return "unable to fetch upstream data: " + err.err.Error()
+}
+
+// errorStatus returns the status code to respond an error of fetching
+// and decoding data. Failure to fetch or invalid data from HKO is
+// reported as bad gateway.
+func errorStatus(err error) int {
+	switch err.(type) {
+	case upstreamError, hkodata.ParseError:
+		return http.StatusBadGateway
+	}
+	return http.StatusInternalServerError
+}
+
+// fetch requests the source. Failure to connect or response other than
+// 200 OK are reported as upstreamError.
+func fetch(source string) (*http.Response, error) {
+	resp, err := http.Get(source)
+	if err != nil {
+		return nil, upstreamError{err}
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, upstreamError{fmt.Errorf("%s responded %s", source, resp.Status)}
+	}
+	return resp, nil
+}
+
+// fetchOneJSON fetches and decodes the HKO homepage bundle
+func fetchOneJSON(opts ...hkodata.DecodeOption) (*hkodata.OneJSON, error) {
+	resp, err := fetch(sourceOneJSON)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return hkodata.DecodeOneJSON(resp.Body, opts...)
+}
+
+// forecastTerm finds the glossary term referenced in the current local
+// forecast. The forecast is nil if the term is not referenced.
+func forecastTerm(code string) (term glossary.Term, forecast *hkodata.LocalForecast, err error) {
+	// warnings in unrelated fields do not matter
+	one, err := fetchOneJSON()
+	if _, err = splitWarnings(err); err != nil {
+		return
+	}
+	forecast, err = one.LocalForecast()
+	if _, err = splitWarnings(err); err != nil {
+		return
+	}
+	texts := []hkodata.ForecastText{
+		forecast.GeneralSituation,
+		forecast.TCInfo,
+		forecast.FireDangerWarning,
+		forecast.ForecastDesc,
+		forecast.OutlookContent,
+	}
+	for _, text := range texts {
+		for _, ref := range text.Terms {
+			if ref.Code == code {
+				term = glossary.TermOf(text, ref)
+				return
+			}
+		}
+	}
+	return term, nil, nil
+}
+
+// snapshot keeps the latest body of an upstream source in process until
+// the data in it expire. Handlers computing results per request (e.g. for
+// any coordinates) share it instead of fetching HKO on every cache miss.
+// Concurrent fetches of an expired snapshot are coalesced.
+type snapshot struct {
+	source  string
+	expires func(body []byte) time.Time
+	group   singleflight.Group
+	mutex   sync.RWMutex
+	body    []byte
+	expiry  time.Time
+}
+
+// snapshotMinTTL and snapshotMaxTTL bound how long a snapshot is kept:
+// data already expired (e.g. HKO is late to update) are refetched shortly
+// and no data are kept for longer than the maximum
+const (
+	snapshotMinTTL = 30 * time.Second
+	snapshotMaxTTL = 10 * time.Minute
+)
+
+// newSnapshot creates a snapshot of the source. The expires function
+// finds the expiry of the data in a body, or zero time if unknown.
+func newSnapshot(source string, expires func(body []byte) time.Time) *snapshot {
+	return &snapshot{source: source, expires: expires}
+}
+
+// get returns the body of the snapshot, fetching the source if the
+// snapshot is empty or expired
+func (snapshot *snapshot) get() ([]byte, error) {
+	snapshot.mutex.RLock()
+	body, expiry := snapshot.body, snapshot.expiry
+	snapshot.mutex.RUnlock()
+	if body != nil && time.Now().Before(expiry) {
+		return body, nil
+	}
+
+	fetched, err := snapshot.group.Do(snapshot.source, func() (interface{}, error) {
+		resp, err := fetch(snapshot.source)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, upstreamError{err}
+		}
+
+		now := time.Now()
+		expiry := snapshot.expires(body)
+		if expiry.Before(now.Add(snapshotMinTTL)) {
+			expiry = now.Add(snapshotMinTTL)
+		} else if expiry.After(now.Add(snapshotMaxTTL)) {
+			expiry = now.Add(snapshotMaxTTL)
+		}
+		snapshot.mutex.Lock()
+		snapshot.body, snapshot.expiry = body, expiry
+		snapshot.mutex.Unlock()
+		return body, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fetched.([]byte), nil
+}
+
+var currentWeatherSnapshot = newSnapshot(sourceCurrentWeather, func(body []byte) time.Time {
+	if data, _ := hkodata.DecodeCurrentWeather(bytes.NewReader(body)); data != nil {
+		return data.Expires()
+	}
+	return time.Time{}
+})
+
+var regionsSnapshot = newSnapshot(sourceRegionJSON, func(body []byte) time.Time {
+	if regions, _ := hkodata.DecodeRegionJSON(bytes.NewReader(body)); regions != nil {
+		return regions.Expires()
+	}
+	return time.Time{}
+})
+
+// fetchCurrentWeather decodes the current weather report from its
+// snapshot
+func fetchCurrentWeather(opts ...hkodata.DecodeOption) (*hkodata.CurrentWeather, error) {
+	body, err := currentWeatherSnapshot.get()
+	if err != nil {
+		return nil, err
+	}
+	return hkodata.DecodeCurrentWeather(bytes.NewReader(body), opts...)
+}
+
+// fetchRegions decodes the regional weather readings from its snapshot
+func fetchRegions(opts ...hkodata.DecodeOption) (*hkodata.Regions, error) {
+	body, err := regionsSnapshot.get()
+	if err != nil {
+		return nil, err
+	}
+	regions, err := hkodata.DecodeRegionJSON(bytes.NewReader(body), opts...)
+	if regions != nil {
+		history.checkSpikes(regions)
+	}
+	return regions, err
+}
+
+// regionHistory keeps the latest 2 reports of regions data to flag
+// spikes in readings. Reports are cloned as served data would be
+// converted to other units.
+type regionHistory struct {
+	sync.Mutex
+	latest   *hkodata.Regions
+	previous *hkodata.Regions
+}
+
+var history regionHistory
+
+// checkSpikes records the report, if newer than the latest, and flags
+// spikes against the report before it
+func (history *regionHistory) checkSpikes(regions *hkodata.Regions) {
+	history.Lock()
+	defer history.Unlock()
+	if history.latest == nil || regions.PubDate.After(history.latest.PubDate) {
+		history.previous, history.latest = history.latest, regions.Clone()
+	}
+	if history.previous != nil && regions.PubDate.Equal(history.latest.PubDate) {
+		regions.CheckSpikes(history.previous)
+	}
+}
+
+// parseCoordinates reads the latitude and longitude (in degrees) from
+// the query parameters "lat" and "lon"
+func parseCoordinates(r *http.Request) (lat, lon float64, err error) {
+	latStr, lonStr := r.URL.Query().Get("lat"), r.URL.Query().Get("lon")
+	if lat, err = strconv.ParseFloat(latStr, 64); err != nil || lat < -90 || lat > 90 {
+		err = fmt.Errorf("invalid lat: %#v (must be degrees between -90 and 90)", latStr)
+		return
+	}
+	if lon, err = strconv.ParseFloat(lonStr, 64); err != nil || lon < -180 || lon > 180 {
+		err = fmt.Errorf("invalid lon: %#v (must be degrees between -180 and 180)", lonStr)
+		return
+	}
+	return
+}
+
+// serveOneJSON generates a handler to serve data extracted from the
+// HKO homepage bundle. The extract function returns the data to serve
+// and its last modified time. Data partially extracted are served with
+// the warnings. Expired data may be served within the stale windows.
+func serveOneJSON(stale staleWindows, extract func(one *hkodata.OneJSON, opts ...hkodata.DecodeOption) (data hkodata.Expirer, lastModified time.Time, err error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		units, err := unitSystem(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err, "")
+			return
+		}
+
+		// get contexted loggers
+		_, errorLog := ctxlog.GetLoggers(r)
+
+		opts, err := decodeOptions(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err, "")
+			return
+		}
+
+		// fetch and decode the JSON bundle
+		one, err := fetchOneJSON(opts...)
+		if err != nil {
+			errorLog.Log("message", err.Error())
+		}
+		warnings, err := splitWarnings(err)
+		if err != nil {
+			writeError(w, errorStatus(err), err, sourceOneJSON)
+			return
+		}
+		data, lastModified, err := extract(one, opts...)
+		if err != nil {
+			errorLog.Log("message", err.Error())
+		}
+		dataWarnings, err := splitWarnings(err)
+		if err != nil {
+			writeError(w, errorStatus(err), err, sourceOneJSON)
+			return
+		}
+		warnings = append(warnings, dataWarnings...)
+
+		setCacheHeaders(w, lastModified, data.Expires(), stale)
+		writeResponse(w, units, response{
+			Data:     data,
+			Source:   sourceOneJSON,
+			Notice:   noticeNonpublicAPI,
+			Warnings: warnings,
+		})
+	}
+}
+
+// setCacheHeaders sets the caching related headers of a response
+// by the publish date and expiration time of the data
+func setCacheHeaders(w http.ResponseWriter, lastModified, expires time.Time, stale staleWindows) {
+	w.Header().Set("Last-Modified", rfc2616(lastModified))
+	w.Header().Set("Expires", rfc2616(expires))
+	if stale == noStale {
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d, must-revalidate", maxAge(expires)))
+	} else {
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d, stale-while-revalidate=%d, stale-if-error=%d",
+			maxAge(expires), stale.whileRevalidate/time.Second, stale.ifError/time.Second))
+	}
+	if expires.Before(time.Now()) {
+		// Add grace expiration 5 minutes, if already expired
+		w.Header().Set("X-Grace-Expires", rfc2616(time.Now().Add(5*time.Minute)))
+	}
+}
+
 func main() {
 
 	apiHandler := http.NewServeMux()
@@ -128,121 +531,498 @@ func main() {
 	apiHandler.HandleFunc("/hko/CurrentWeather.json", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json; charset=utf-8")
 
+		units, err := unitSystem(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err, "")
+			return
+		}
+
 		// get contexted loggers
 		_, errorLog := ctxlog.GetLoggers(r)
 
-		req, err := http.Get("http://rss.weather.gov.hk/rss/CurrentWeather.xml")
+		opts, err := decodeOptions(r)
 		if err != nil {
-			errorLog.Log("message", err.Error())
+			writeError(w, http.StatusBadRequest, err, "")
 			return
 		}
 
-		// prepare encoder for output
-		enc := json.NewEncoder(w)
+		// include comfort indices
+		comfort, err := boolParam(r, "comfort")
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err, "")
+			return
+		}
 
-		// decode the RSS
-		data, err := hkodata.DecodeCurrentWeather(req.Body)
+		// fetch and decode the RSS
+		// (partially parsed data are served with warnings)
+		data, err := fetchCurrentWeather(opts...)
 		if err != nil {
 			errorLog.Log("message", err.Error())
-			w.WriteHeader(http.StatusInternalServerError)
-			enc.Encode(struct {
-				Status  int    `json:"status"`
-				Message string `json:"message"`
-			}{
-				Status:  http.StatusInternalServerError,
-				Message: err.Error(),
-			})
+		}
+		warnings, err := splitWarnings(err)
+		if err != nil {
+			writeError(w, errorStatus(err), err, sourceCurrentWeather)
 			return
 		}
 
+		if comfort {
+			data.Comfort = data.ComfortIndices()
+		}
+
 		// return formatted data
 
 		// TODO: properly handle If-Modified-Since request
 		// TODO: properly generate ETag
 
-		w.Header().Set("Last-Modified", rfc2616(data.PubDate))
-		w.Header().Set("Expires", rfc2616(data.Expires()))
-		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge(data.Expires())))
-		if data.Expires().Before(time.Now()) {
-			// Add grace expiration 5 minutes, if already expired
-			w.Header().Set("X-Grace-Expires", rfc2616(time.Now().Add(5*time.Minute)))
-		}
-		w.WriteHeader(http.StatusOK)
-		enc.Encode(struct {
-			Status int                    `json:"status"`
-			Data   hkodata.CurrentWeather `json:"data"`
-			Source string                 `json:"source"`
-			// Raw    string                 `json:"raw_data"`
-		}{
-			Status: http.StatusOK,
-			Data:   *data,
-			Source: "http://rss.weather.gov.hk/rss/CurrentWeather.xml",
-			// Raw:    data.Raw,
+		setCacheHeaders(w, data.PubDate, data.Expires(), defaultStale)
+		writeResponse(w, units, response{
+			Data:     *data,
+			Source:   sourceCurrentWeather,
+			Warnings: warnings,
 		})
 	})
 
 	apiHandler.HandleFunc("/hkoPrivate/region.json", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json; charset=utf-8")
 
+		units, err := unitSystem(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err, "")
+			return
+		}
+
 		// get contexted loggers
 		_, errorLog := ctxlog.GetLoggers(r)
 
-		source := "http://www.hko.gov.hk/wxinfo/json/region_json.xml"
+		source := sourceRegionJSON
 
-		req, err := http.Get(source)
+		opts, err := decodeOptions(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err, "")
+			return
+		}
+
+		// include comfort indices
+		comfort, err := boolParam(r, "comfort")
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err, "")
+			return
+		}
+
+		// fetch and decode the JSON
+		data, err := fetchRegions(opts...)
 		if err != nil {
 			errorLog.Log("message", err.Error())
+		}
+		warnings, err := splitWarnings(err)
+		if err != nil {
+			writeError(w, errorStatus(err), err, source)
 			return
 		}
 
-		// prepare encoder for output
-		enc := json.NewEncoder(w)
+		if comfort {
+			data.ComputeComfort()
+		}
 
-		// decode the RSS
-		data, err := hkodata.DecodeRegionJSON(req.Body)
+		// TODO: properly handle If-Modified-Since request
+		// TODO: properly generate ETag
+
+		setCacheHeaders(w, data.PubDate, data.Expires(), defaultStale)
+		writeResponse(w, units, response{
+			Data:     *data,
+			Source:   source,
+			Notice:   noticeNonpublicAPI,
+			Warnings: warnings,
+		})
+
+	})
+
+	apiHandler.HandleFunc("/hkoPrivate/one.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		// raw strings of HKO cannot be converted
+		if r.URL.Query().Get("units") != "" {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("units is not supported for raw data"), "")
+			return
+		}
+
+		// get contexted loggers
+		_, errorLog := ctxlog.GetLoggers(r)
+
+		opts, err := decodeOptions(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err, "")
+			return
+		}
+
+		// fetch and decode the JSON bundle
+		data, err := fetchOneJSON(opts...)
 		if err != nil {
 			errorLog.Log("message", err.Error())
-			w.WriteHeader(http.StatusInternalServerError)
-			enc.Encode(struct {
-				Status  int    `json:"status"`
-				Message string `json:"message"`
-				Source  string `json:"source"`
-			}{
-				Status:  http.StatusInternalServerError,
-				Message: err.Error(),
-				Source:  source,
-			})
+		}
+		warnings, err := splitWarnings(err)
+		if err != nil {
+			writeError(w, errorStatus(err), err, sourceOneJSON)
 			return
 		}
 
 		// TODO: properly handle If-Modified-Since request
 		// TODO: properly generate ETag
 
-		w.Header().Set("Last-Modified", rfc2616(data.PubDate))
-		w.Header().Set("Expires", rfc2616(data.Expires()))
-		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge(data.Expires())))
-		if data.Expires().Before(time.Now()) {
-			// Add grace expiration 5 minutes, if already expired
-			w.Header().Set("X-Grace-Expires", rfc2616(time.Now().Add(5*time.Minute)))
+		setCacheHeaders(w, data.PubDate, data.Expires(), defaultStale)
+		writeRawResponse(w, response{
+			Data:     *data,
+			Source:   sourceOneJSON,
+			Notice:   noticeNonpublicAPI,
+			Warnings: warnings,
+		})
+
+	})
+
+	apiHandler.HandleFunc("/hkoPrivate/nineDayForecast.json", serveOneJSON(defaultStale, func(one *hkodata.OneJSON, opts ...hkodata.DecodeOption) (hkodata.Expirer, time.Time, error) {
+		data, err := one.NineDayForecast(opts...)
+		if data == nil {
+			return nil, time.Time{}, err
 		}
-		w.WriteHeader(http.StatusOK)
+		return data, data.PubDate, err
+	}))
+
+	apiHandler.HandleFunc("/hkoPrivate/tide.json", serveOneJSON(defaultStale, func(one *hkodata.OneJSON, opts ...hkodata.DecodeOption) (hkodata.Expirer, time.Time, error) {
+		data, err := one.TideTable(opts...)
+		if data == nil {
+			return nil, time.Time{}, err
+		}
+		return data, one.PubDate, err
+	}))
+
+	apiHandler.HandleFunc("/hkoPrivate/astronomy.json", serveOneJSON(defaultStale, func(one *hkodata.OneJSON, opts ...hkodata.DecodeOption) (hkodata.Expirer, time.Time, error) {
+		data, err := one.Astronomy(opts...)
+		if data == nil {
+			return nil, time.Time{}, err
+		}
+		return data, one.PubDate, err
+	}))
+
+	apiHandler.HandleFunc("/hkoPrivate/localForecast.json", serveOneJSON(defaultStale, func(one *hkodata.OneJSON, opts ...hkodata.DecodeOption) (hkodata.Expirer, time.Time, error) {
+		data, err := one.LocalForecast(opts...)
+		if data == nil {
+			return nil, time.Time{}, err
+		}
+		return data, data.PubDate, err
+	}))
+
+	apiHandler.HandleFunc("/hkoPrivate/uvForecast.json", serveOneJSON(defaultStale, func(one *hkodata.OneJSON, opts ...hkodata.DecodeOption) (hkodata.Expirer, time.Time, error) {
+		data, err := one.UVForecast(opts...)
+		if data == nil {
+			return nil, time.Time{}, err
+		}
+		return data, data.PubDate, err
+	}))
+
+	apiHandler.HandleFunc("/hkoPrivate/uvIndex.json", serveOneJSON(defaultStale, func(one *hkodata.OneJSON, opts ...hkodata.DecodeOption) (hkodata.Expirer, time.Time, error) {
+		data, err := one.UVReading(opts...)
+		if data == nil {
+			return nil, time.Time{}, err
+		}
+		return data, data.PubDate, err
+	}))
+
+	apiHandler.HandleFunc("/hkoPrivate/lightning.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		// time window (in minutes) to check for lightning
+		within := 30
+		if withinStr := r.URL.Query().Get("within"); withinStr != "" {
+			var err error
+			if within, err = strconv.Atoi(withinStr); err != nil || within <= 0 || within > 24*60 {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("invalid within: %#v (must be minutes between 1 and 1440)", withinStr), "")
+				return
+			}
+		}
+
+		serveOneJSON(noStale, func(one *hkodata.OneJSON, opts ...hkodata.DecodeOption) (hkodata.Expirer, time.Time, error) {
+			lightning, err := one.Lightning(opts...)
+			if lightning == nil {
+				return nil, time.Time{}, err
+			}
+			// Recorded stays unknown without evidence of lightning strikes
+			// (see hkodata.LightningSummary), rather than a false alarm
+			data := lightning.Summary(time.Now().Add(-time.Duration(within) * time.Minute))
+			return data, data.PubDate, err
+		})(w, r)
+	})
+
+	apiHandler.HandleFunc("/hkoPrivate/weatherTips.json", serveOneJSON(defaultStale, func(one *hkodata.OneJSON, opts ...hkodata.DecodeOption) (hkodata.Expirer, time.Time, error) {
+		tips, err := one.SpecialWeatherTips(opts...)
+		if tips == nil {
+			return nil, time.Time{}, err
+		}
+		return tips.Active(), tips.PubDate, err
+	}))
 
-		enc.Encode(struct {
-			Status int             `json:"status"`
-			Data   hkodata.Regions `json:"data"`
-			Source string          `json:"source"`
-			Notice string          `json:"notice"`
-			// Raw    string                 `json:"raw_data"`
-		}{
-			Status: http.StatusOK,
-			Data:   *data,
-			Source: source,
-			Notice: noticeNonpublicAPI,
-			// Raw:    data.Raw,
+	apiHandler.HandleFunc("/glossary/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		units, err := unitSystem(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err, "")
+			return
+		}
+
+		code := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/glossary/"), ".json")
+
+		// bundled data, only changes with new release
+		expires := time.Now().Add(24 * time.Hour)
+		term, ok := glossary.LookupTerm(code)
+		if !ok {
+			// find the name of term not bundled in the current forecast
+			var forecast *hkodata.LocalForecast
+			if term, forecast, err = forecastTerm(code); err != nil {
+				_, errorLog := ctxlog.GetLoggers(r)
+				errorLog.Log("message", err.Error())
+				writeError(w, errorStatus(err), err, "")
+				return
+			}
+			if forecast == nil {
+				writeError(w, http.StatusNotFound, fmt.Errorf("glossary term %#v not found", code), "")
+				return
+			}
+			expires = forecast.Expires()
+		}
+		w.Header().Set("Expires", rfc2616(expires))
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge(expires)))
+		writeResponse(w, units, response{
+			Data: term,
 		})
+	})
+
+	apiHandler.HandleFunc("/nearby.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		units, err := unitSystem(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err, "")
+			return
+		}
+
+		// get contexted loggers
+		_, errorLog := ctxlog.GetLoggers(r)
+
+		lat, lon, err := parseCoordinates(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err, "")
+			return
+		}
+
+		// number of nearest stations to list
+		n := 5
+		if nStr := r.URL.Query().Get("n"); nStr != "" {
+			if n, err = strconv.Atoi(nStr); err != nil || n <= 0 || n > 50 {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("invalid n: %#v (must be between 1 and 50)", nStr), "")
+				return
+			}
+		}
+
+		opts, err := decodeOptions(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err, "")
+			return
+		}
+
+		// include comfort indices
+		comfort, err := boolParam(r, "comfort")
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err, "")
+			return
+		}
+
+		// fetch and decode both reports
+		regions, err := fetchRegions(opts...)
+		if err != nil {
+			errorLog.Log("message", err.Error())
+		}
+		warnings, err := splitWarnings(err)
+		if err != nil {
+			writeError(w, errorStatus(err), err, sourceRegionJSON)
+			return
+		}
+		currentWeather, err := fetchCurrentWeather(opts...)
+		if err != nil {
+			errorLog.Log("message", err.Error())
+		}
+		currentWarnings, err := splitWarnings(err)
+		if err != nil {
+			writeError(w, errorStatus(err), err, sourceCurrentWeather)
+			return
+		}
+		warnings = append(warnings, currentWarnings...)
 
+		data := hkodata.Nearby(lat, lon, regions, currentWeather, n)
+		if len(data.Stations) == 0 {
+			writeError(w, http.StatusNotFound, fmt.Errorf("no station within %gkm of the point", hkodata.NearbyRange), "")
+			return
+		}
+		if comfort {
+			data.Conditions.Comfort = data.Conditions.ComfortIndices()
+			for i := range data.Stations {
+				data.Stations[i].Comfort = data.Stations[i].ComfortIndices()
+			}
+		}
+
+		setCacheHeaders(w, data.PubDate, data.Expires(), defaultStale)
+		writeResponse(w, units, response{
+			Data:     data,
+			Source:   sourceRegionJSON,
+			Notice:   noticeNonpublicAPI,
+			Warnings: warnings,
+		})
+	})
+
+	apiHandler.HandleFunc("/heatstress.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		units, err := unitSystem(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err, "")
+			return
+		}
+
+		// get contexted loggers
+		_, errorLog := ctxlog.GetLoggers(r)
+
+		opts, err := decodeOptions(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err, "")
+			return
+		}
+
+		// fetch and decode both reports
+		regions, err := fetchRegions(opts...)
+		if err != nil {
+			errorLog.Log("message", err.Error())
+		}
+		warnings, err := splitWarnings(err)
+		if err != nil {
+			writeError(w, errorStatus(err), err, sourceRegionJSON)
+			return
+		}
+		currentWeather, err := fetchCurrentWeather(opts...)
+		if err != nil {
+			errorLog.Log("message", err.Error())
+		}
+		currentWarnings, err := splitWarnings(err)
+		if err != nil {
+			writeError(w, errorStatus(err), err, sourceCurrentWeather)
+			return
+		}
+		warnings = append(warnings, currentWarnings...)
+
+		// UV index for solar load is optional, estimate without it
+		// if unavailable
+		var uv *hkodata.UVReading
+		one, err := fetchOneJSON()
+		if err != nil {
+			errorLog.Log("message", err.Error())
+		}
+		if one != nil {
+			if uv, err = one.UVReading(); err != nil {
+				errorLog.Log("message", err.Error())
+			}
+		}
+
+		data := hkodata.NewHeatStressReport(regions, currentWeather, uv)
+
+		setCacheHeaders(w, data.PubDate, data.Expires(), defaultStale)
+		writeResponse(w, units, response{
+			Data:     data,
+			Source:   sourceRegionJSON,
+			Notice:   noticeNonpublicAPI,
+			Warnings: warnings,
+		})
+	})
+
+	apiHandler.HandleFunc("/estimate.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		units, err := unitSystem(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err, "")
+			return
+		}
+
+		// get contexted loggers
+		_, errorLog := ctxlog.GetLoggers(r)
+
+		lat, lon, err := parseCoordinates(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err, "")
+			return
+		}
+
+		// elevation (in metres) of the point, if known
+		var estimateOpts []hkodata.EstimateOption
+		if elevStr := r.URL.Query().Get("elev"); elevStr != "" {
+			elev, err := strconv.ParseFloat(elevStr, 64)
+			if err != nil || elev < -100 || elev > 9000 {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("invalid elev: %#v (must be metres between -100 and 9000)", elevStr), "")
+				return
+			}
+			estimateOpts = append(estimateOpts, hkodata.AtElevation(elev))
+		}
+
+		opts, err := decodeOptions(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err, "")
+			return
+		}
+
+		regions, err := fetchRegions(opts...)
+		if err != nil {
+			errorLog.Log("message", err.Error())
+		}
+		warnings, err := splitWarnings(err)
+		if err != nil {
+			writeError(w, errorStatus(err), err, sourceRegionJSON)
+			return
+		}
+
+		data := regions.Estimate(lat, lon, estimateOpts...)
+		if len(data.Sources) == 0 {
+			writeError(w, http.StatusNotFound, fmt.Errorf("no station with readings near the point"), "")
+			return
+		}
+
+		setCacheHeaders(w, data.PubDate, data.Expires(), defaultStale)
+		writeResponse(w, units, response{
+			Data:     data,
+			Source:   sourceRegionJSON,
+			Notice:   noticeNonpublicAPI,
+			Warnings: warnings,
+		})
 	})
 
+	apiHandler.HandleFunc("/stations.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		units, err := unitSystem(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err, "")
+			return
+		}
+
+		// bundled data, only changes with new release
+		expires := time.Now().Add(24 * time.Hour)
+		w.Header().Set("Expires", rfc2616(expires))
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge(expires)))
+		writeResponse(w, units, response{
+			Data: hkodata.Stations(),
+		})
+	})
+
+	// query parameters read by the handlers above, the only ones the
+	// cached responses vary by
+	httpcache.SetQueryParams("units", "strict", "comfort", "lat", "lon", "n", "elev", "within")
+
 	middlewares := chain(
 		genRequestID,
 		timeRequest,
@@ -254,7 +1034,7 @@ func main() {
 	root.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Add("Content-Type", "text/html; charset=utf8")
 		w.WriteHeader(http.StatusOK)
-		fmt.Fprintf(w, `<html><h1>Simple Hong Kong Weather API</h1><ul><li><a href="/api/hko/CurrentWeather.json">Current Weather</a></li><li><a href="/api/hkoPrivate/region.json">Region Weather</a></li></ul></html>`)
+		fmt.Fprintf(w, `<html><h1>Simple Hong Kong Weather API</h1><ul><li><a href="/api/hko/CurrentWeather.json">Current Weather</a></li><li><a href="/api/hkoPrivate/region.json">Region Weather</a></li><li><a href="/api/hkoPrivate/one.json">HKO Homepage Bundle</a></li><li><a href="/api/hkoPrivate/localForecast.json">Local Weather Forecast</a></li><li><a href="/api/hkoPrivate/nineDayForecast.json">9-day Weather Forecast</a></li><li><a href="/api/hkoPrivate/tide.json">Tide Table</a></li><li><a href="/api/hkoPrivate/astronomy.json">Sun and Moon</a></li><li><a href="/api/hkoPrivate/uvIndex.json">UV Index</a></li><li><a href="/api/hkoPrivate/uvForecast.json">UV Index Forecast</a></li><li><a href="/api/hkoPrivate/lightning.json">Lightning</a></li><li><a href="/api/hkoPrivate/weatherTips.json">Special Weather Tips</a></li><li><a href="/api/stations.json">Weather Stations</a></li><li><a href="/api/nearby.json?lat=22.3019&amp;lon=114.1742">Nearby Weather</a></li><li><a href="/api/estimate.json?lat=22.2800&amp;lon=114.1600">Estimated Weather</a></li><li><a href="/api/heatstress.json">Heat Stress</a></li></ul></html>`)
 	})
 
 	fmt.Printf("listen at port %d\n", port)
@@ -14,9 +14,8 @@ require (
 	github.com/mmcdole/gofeed v0.0.0-20161202021325-0058183a2948
 	github.com/mmcdole/goxpp v0.0.0-20160419160217-e38884aa48c1 // indirect
 	github.com/tonnerre/golang-pretty v0.0.0-20130925195953-e7fccc03e91b
-	go4.org v0.0.0-20161118210015-09d86de304dc // indirect
-	golang.org/x/net v0.0.0-20161215194249-45e771701b81 // indirect
+	go4.org v0.0.0-20161118210015-09d86de304dc
+	golang.org/x/net v0.0.0-20161215194249-45e771701b81
 	golang.org/x/text v0.0.0-20161216064924-a49bea13b776 // indirect
-	gopkg.in/go-redis/cache.v5 v5.0.2
 	gopkg.in/redis.v5 v5.1.5
 )
@@ -0,0 +1,111 @@
+package hkodata
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Astronomy contains sun and moon rise / set times of a date
+type Astronomy struct {
+	Date      time.Time
+	Sunrise   time.Time
+	Sunset    time.Time
+	DayLength time.Duration
+
+	// Moonrise and Moonset are nil if the moon does not rise or set
+	// on the date.
+	Moonrise *time.Time `json:"Moonrise,omitempty"`
+	Moonset  *time.Time `json:"Moonset,omitempty"`
+
+	// MoonUpAtMidnight is true if the moon is already above the horizon
+	// at the beginning of the date (i.e. the moonset happens before the
+	// moonrise of the date).
+	MoonUpAtMidnight bool
+}
+
+// MarshalJSON implements json.Marshaler
+// (DayLength is in minutes instead of nanoseconds)
+func (astronomy Astronomy) MarshalJSON() ([]byte, error) {
+	type plainAstronomy Astronomy
+	return json.Marshal(struct {
+		plainAstronomy
+		DayLength int64
+	}{
+		plainAstronomy: plainAstronomy(astronomy),
+		DayLength:      int64(astronomy.DayLength / time.Minute),
+	})
+}
+
+// Expires implements Expirer interface
+// (the data is for a single date)
+func (astronomy Astronomy) Expires() time.Time {
+	return astronomy.Date.AddDate(0, 0, 1)
+}
+
+// parseOptionalClock parses a "hh:mm" clock time string on the given date.
+// Empty or placeholder strings (e.g. "--:--") will result in nil.
+func parseOptionalClock(date time.Time, str string) (t *time.Time, err error) {
+	if strings.Trim(str, " -:") == "" {
+		return
+	}
+	parsed, err := parseClock(date, str)
+	if err != nil {
+		return
+	}
+	t = &parsed
+	return
+}
+
+// Astronomy parses the CMN section into Astronomy
+func (one OneJSON) Astronomy(opts ...DecodeOption) (astronomy *Astronomy, err error) {
+	cmn := one.CMN
+	parseErrors := make(ParseError, 0, 4)
+
+	// forecastDate is the date the times refer to
+	dateStr := cmn.ForecastDate
+	if dateStr == "" {
+		dateStr = cmn.GregorianDate
+	}
+	astronomy = &Astronomy{}
+	if astronomy.Date, err = time.ParseInLocation("20060102", dateStr, HKT); err != nil {
+		parseErrors.fatal("CMN.forecastDate", dateStr, "unable to parse date")
+		return nil, parseErrors
+	}
+
+	if astronomy.Sunrise, err = parseClock(astronomy.Date, cmn.SunriseTime); err != nil {
+		parseErrors.warn("CMN.sunriseTime", cmn.SunriseTime, "%s", err)
+	}
+	if astronomy.Sunset, err = parseClock(astronomy.Date, cmn.SunsetTime); err != nil {
+		parseErrors.warn("CMN.sunsetTime", cmn.SunsetTime, "%s", err)
+	}
+	if !astronomy.Sunrise.IsZero() && !astronomy.Sunset.IsZero() {
+		// sunset crossed midnight into the next day
+		if astronomy.Sunset.Before(astronomy.Sunrise) {
+			astronomy.Sunset = astronomy.Sunset.AddDate(0, 0, 1)
+		}
+		astronomy.DayLength = astronomy.Sunset.Sub(astronomy.Sunrise)
+	}
+
+	if astronomy.Moonrise, err = parseOptionalClock(astronomy.Date, cmn.MoonriseTime); err != nil {
+		parseErrors.warn("CMN.moonriseTime", cmn.MoonriseTime, "%s", err)
+	}
+	if astronomy.Moonset, err = parseOptionalClock(astronomy.Date, cmn.MoonsetTime); err != nil {
+		parseErrors.warn("CMN.moonsetTime", cmn.MoonsetTime, "%s", err)
+	}
+
+	// the moon is up at midnight if it sets before it rises on the date,
+	// or if it sets without rising on the date at all
+	switch {
+	case astronomy.Moonset != nil && astronomy.Moonrise != nil:
+		astronomy.MoonUpAtMidnight = astronomy.Moonset.Before(*astronomy.Moonrise)
+	case astronomy.Moonset != nil:
+		astronomy.MoonUpAtMidnight = true
+	}
+
+	err = parseErrors.result(newDecodeOptions(opts))
+	if parseErrors.Fatal() {
+		astronomy = nil
+	}
+	return
+}
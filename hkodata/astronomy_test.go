@@ -0,0 +1,100 @@
+package hkodata_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/yookoala/weatherhk/hkodata"
+)
+
+func TestOneJSON_Astronomy(t *testing.T) {
+	file, err := os.Open("./test/one_json_uc.201612191917.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	defer file.Close()
+
+	one, err := hkodata.DecodeOneJSON(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	astronomy, err := one.Astronomy()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if want, have := time.Date(2016, time.December, 19, 6, 57, 0, 0, hkodata.HKT), astronomy.Sunrise; !want.Equal(have) {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+	if want, have := time.Date(2016, time.December, 19, 17, 44, 0, 0, hkodata.HKT), astronomy.Sunset; !want.Equal(have) {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+	if want, have := 10*time.Hour+47*time.Minute, astronomy.DayLength; want != have {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+	if astronomy.Moonrise == nil {
+		t.Fatalf("expected moonrise, got nil")
+	}
+	if want, have := time.Date(2016, time.December, 19, 23, 9, 0, 0, hkodata.HKT), *astronomy.Moonrise; !want.Equal(have) {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+	if astronomy.Moonset == nil {
+		t.Fatalf("expected moonset, got nil")
+	}
+	if want, have := time.Date(2016, time.December, 19, 11, 14, 0, 0, hkodata.HKT), *astronomy.Moonset; !want.Equal(have) {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+	if want, have := true, astronomy.MoonUpAtMidnight; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
+
+func TestOneJSON_Astronomy_noMoonrise(t *testing.T) {
+	one := hkodata.OneJSON{
+		CMN: hkodata.OneJSONCMN{
+			ForecastDate: "20161220",
+			SunriseTime:  "06:58",
+			SunsetTime:   "17:44",
+			MoonriseTime: "--:--",
+			MoonsetTime:  "12:02",
+		},
+	}
+
+	astronomy, err := one.Astronomy()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if astronomy.Moonrise != nil {
+		t.Errorf("expected nil, got %s", astronomy.Moonrise)
+	}
+	if want, have := true, astronomy.MoonUpAtMidnight; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := time.Date(2016, time.December, 21, 0, 0, 0, 0, hkodata.HKT), astronomy.Expires(); !want.Equal(have) {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+}
+
+func TestAstronomy_MarshalJSON(t *testing.T) {
+	astronomy := hkodata.Astronomy{
+		Date:      time.Date(2016, time.December, 19, 0, 0, 0, 0, hkodata.HKT),
+		DayLength: 10*time.Hour + 47*time.Minute,
+	}
+	b, err := json.Marshal(astronomy)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if want, have := float64(647), decoded["DayLength"]; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if _, ok := decoded["Sunrise"]; !ok {
+		t.Errorf("expected Sunrise in %s", b)
+	}
+}
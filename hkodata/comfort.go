@@ -0,0 +1,111 @@
+package hkodata
+
+import "math"
+
+// ComfortIndices contains indices of how hot the weather feels like,
+// derived from temperature, relative humidity and wind speed
+type ComfortIndices struct {
+	DewPoint            Temperature
+	HeatIndex           Temperature
+	Humidex             Temperature
+	ApparentTemperature Temperature
+	WindAdjusted        bool // if wind speed is used in ApparentTemperature
+}
+
+// vapourPressure returns the water vapour pressure (in hPa) of air of
+// the temperature and relative humidity
+func vapourPressure(temp Temperature, rh RelativeHumidity) float64 {
+	return float64(rh) * 6.105 * math.Exp(17.27*float64(temp)/(237.7+float64(temp)))
+}
+
+// DewPoint returns the dew point of air of the temperature and relative
+// humidity, by the Magnus formula
+func DewPoint(temp Temperature, rh RelativeHumidity) Temperature {
+	const a, b = 17.625, 243.04
+	gamma := math.Log(float64(rh)) + a*float64(temp)/(b+float64(temp))
+	return Temperature(b * gamma / (a - gamma))
+}
+
+// HeatIndex returns the heat index of the temperature and relative
+// humidity, by the regression of the US National Weather Service
+func HeatIndex(temp Temperature, rh RelativeHumidity) Temperature {
+	t, r := temp.Fahrenheit(), rh.Percent()
+
+	// simple formula for mild weather
+	hi := 0.5 * (t + 61 + (t-68)*1.2 + r*0.094)
+	if (hi+t)/2 >= 80 {
+		// Rothfusz regression
+		hi = -42.379 + 2.04901523*t + 10.14333127*r -
+			0.22475541*t*r - 0.00683783*t*t - 0.05481717*r*r +
+			0.00122874*t*t*r + 0.00085282*t*r*r - 0.00000199*t*t*r*r
+		switch {
+		case r < 13 && t >= 80 && t <= 112:
+			hi -= (13 - r) / 4 * math.Sqrt((17-math.Abs(t-95))/17)
+		case r > 85 && t >= 80 && t <= 87:
+			hi += (r - 85) / 10 * (87 - t) / 5
+		}
+	}
+	return Temperature((hi - 32) * 5 / 9)
+}
+
+// Humidex returns the humidex of the temperature and relative humidity,
+// as used by Environment Canada
+func Humidex(temp Temperature, rh RelativeHumidity) Temperature {
+	dewPoint := float64(DewPoint(temp, rh))
+	e := 6.11 * math.Exp(5417.7530*(1/273.16-1/(273.15+dewPoint)))
+	return temp + Temperature(0.5555*(e-10))
+}
+
+// ApparentTemperature returns the apparent temperature (in shade) of the
+// temperature, relative humidity and wind speed, by the formula of
+// Steadman (1994). Wind speed is taken as calm if nil.
+func ApparentTemperature(temp Temperature, rh RelativeHumidity, wind *Speed) Temperature {
+	ws := 0.0
+	if wind != nil {
+		ws = wind.MetresPerSecond()
+	}
+	return temp + Temperature(0.33*vapourPressure(temp, rh)-0.70*ws-4.00)
+}
+
+// NewComfortIndices computes the comfort indices of the readings, or
+// returns nil if the relative humidity is not positive
+func NewComfortIndices(temp Temperature, rh RelativeHumidity, wind *Speed) *ComfortIndices {
+	if rh <= 0 {
+		return nil
+	}
+	return &ComfortIndices{
+		DewPoint:            DewPoint(temp, rh),
+		HeatIndex:           HeatIndex(temp, rh),
+		Humidex:             Humidex(temp, rh),
+		ApparentTemperature: ApparentTemperature(temp, rh, wind),
+		WindAdjusted:        wind != nil,
+	}
+}
+
+// ComfortIndices computes the comfort indices of the region readings,
+// or returns nil if temperature or relative humidity is not available.
+// Suspect readings are not used.
+func (region Region) ComfortIndices() *ComfortIndices {
+	region = region.Trusted()
+	if region.CurrentTemp == nil || region.RelativeHumidity == nil {
+		return nil
+	}
+	return NewComfortIndices(*region.CurrentTemp, *region.RelativeHumidity, region.WindSpeed)
+}
+
+// ComputeComfort fills in the Comfort field of all regions
+func (regions *Regions) ComputeComfort() {
+	for i := range regions.Regions {
+		regions.Regions[i].Comfort = regions.Regions[i].ComfortIndices()
+	}
+}
+
+// ComfortIndices computes the comfort indices of the air temperature
+// and relative humidity reported (wind speed is not reported), or returns
+// nil if either is suspect
+func (currentWeather CurrentWeather) ComfortIndices() *ComfortIndices {
+	if len(currentWeather.Quality) > 0 {
+		return nil
+	}
+	return NewComfortIndices(currentWeather.AirTemperature, currentWeather.RelativeHumidity, nil)
+}
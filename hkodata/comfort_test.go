@@ -0,0 +1,68 @@
+package hkodata_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/yookoala/weatherhk/hkodata"
+)
+
+func TestComfortIndices(t *testing.T) {
+	temp, rh := hkodata.Temperature(30), hkodata.RelativeHumidity(.7)
+
+	tests := []struct {
+		name string
+		want float64
+		have hkodata.Temperature
+	}{
+		{"DewPoint", 23.9, hkodata.DewPoint(temp, rh)},
+		{"HeatIndex", 35.0, hkodata.HeatIndex(temp, rh)},
+		{"Humidex", 41.0, hkodata.Humidex(temp, rh)},
+		{"ApparentTemperature", 35.8, hkodata.ApparentTemperature(temp, rh, nil)},
+		{"ApparentTemperature (windy)", 28.8, hkodata.ApparentTemperature(temp, rh, hkodata.NewSpeed(36))},
+
+		// mild weather uses the simple heat index formula
+		{"HeatIndex (mild)", 19.5, hkodata.HeatIndex(20, .5)},
+	}
+	for _, test := range tests {
+		if have := float64(test.have); math.Abs(test.want-have) > 0.5 {
+			t.Errorf("%s: expected about %v, got %v", test.name, test.want, have)
+		}
+	}
+}
+
+func TestRegion_ComfortIndices(t *testing.T) {
+	regions := hkodata.Regions{
+		Regions: []hkodata.Region{
+			{
+				ShortName:        "hko",
+				CurrentTemp:      hkodata.NewTemperature(30),
+				RelativeHumidity: hkodata.NewRelativeHumidity(.7),
+			},
+			{
+				ShortName:   "hkp",
+				CurrentTemp: hkodata.NewTemperature(30),
+			},
+		},
+	}
+	regions.ComputeComfort()
+
+	comfort := regions.Regions[0].Comfort
+	if comfort == nil {
+		t.Fatalf("expected comfort indices, got nil")
+	}
+	if want, have := false, comfort.WindAdjusted; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := hkodata.DewPoint(30, .7), comfort.DewPoint; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if regions.Regions[1].Comfort != nil {
+		t.Errorf("expected nil, got %#v", regions.Regions[1].Comfort)
+	}
+
+	// no division by zero with dry air reported
+	if comfort := hkodata.NewComfortIndices(30, 0, nil); comfort != nil {
+		t.Errorf("expected nil, got %#v", comfort)
+	}
+}
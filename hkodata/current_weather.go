@@ -1,9 +1,11 @@
 package hkodata
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
-	"reflect"
+	"io/ioutil"
 	"regexp"
 	"strconv"
 	"strings"
@@ -54,42 +56,23 @@ func NewSpeed(num float64) *Speed {
 	return &val
 }
 
-// DistrictsTemperature contains Temperature of different districts in HK
-type DistrictsTemperature struct {
-	HongKongObservatory    Temperature
-	KingsPark              Temperature
-	WongChukHang           Temperature
-	TaKwuLing              Temperature
-	LauFauShan             Temperature
-	TaiPo                  Temperature
-	ShaTin                 Temperature
-	TuenMun                Temperature
-	TseungKwanO            Temperature
-	SaiKung                Temperature
-	CheungChau             Temperature
-	ChekLapKok             Temperature
-	ShekKong               Temperature
-	TsuenWanHoKoon         Temperature
-	TsuenWanShingMunValley Temperature
-	HongKongPark           Temperature
-	ShauKeiWan             Temperature
-	KowloonCity            Temperature
-	HappyValley            Temperature
-	WongTaiSin             Temperature
-	Stanley                Temperature
-	KwunTong               Temperature
-	ShamShuiPo             Temperature
-	KaiTakRunwayPark       Temperature
-	YuenLongPark           Temperature
+// StationTemperature contains temperature reading of a weather station
+type StationTemperature struct {
+	ID          string
+	Name        I18nName
+	Temperature Temperature
+	Quality     []QualityFlag `json:",omitempty"` // see QualityFlag
 }
 
 // CurrentWeather contains all information of current weather in HKO's report
 type CurrentWeather struct {
-	PubDate              time.Time
-	AirTemperature       Temperature
-	RelativeHumidity     RelativeHumidity
-	DistrictsTemperature DistrictsTemperature
-	Raw                  string `json:"-"`
+	PubDate          time.Time
+	AirTemperature   Temperature
+	RelativeHumidity RelativeHumidity
+	Stations         map[string]StationTemperature // keyed by station ID
+	Comfort          *ComfortIndices               `json:",omitempty"` // see ComfortIndices
+	Quality          Quality                       `json:",omitempty"` // flags of AirTemperature and RelativeHumidity
+	Raw              string                        `json:"-"`
 }
 
 // Expires implements Expirer interface
@@ -98,97 +81,153 @@ func (currentWeather CurrentWeather) Expires() time.Time {
 	return currentWeather.PubDate.Add(60 * time.Minute)
 }
 
-// ParseError contains all error in parsing
-type ParseError []error
-
-// Error implements error interface
-func (errs ParseError) Error() (msg string) {
-	if len(errs) == 0 {
-		return ""
+// DistrictsTemperature returns temperature of stations keyed by
+// the district names without space and punctuation (e.g. "KingsPark")
+func (currentWeather CurrentWeather) DistrictsTemperature() map[string]Temperature {
+	temps := make(map[string]Temperature, len(currentWeather.Stations))
+	for _, station := range currentWeather.Stations {
+		temps[reNonWord.ReplaceAllString(station.Name.En, "")] = station.Temperature
 	}
+	return temps
+}
+
+// MarshalJSON implements json.Marshaler
+// (keeps the DistrictsTemperature field for backward compatibility)
+func (currentWeather CurrentWeather) MarshalJSON() ([]byte, error) {
+	type plainCurrentWeather CurrentWeather
+	return json.Marshal(struct {
+		plainCurrentWeather
+		DistrictsTemperature map[string]Temperature
+	}{
+		plainCurrentWeather:  plainCurrentWeather(currentWeather),
+		DistrictsTemperature: currentWeather.DistrictsTemperature(),
+	})
+}
 
-	msg = "ParseError:\n"
-	for _, err := range errs {
-		msg += err.Error() + "\n"
+var reNonWord = regexp.MustCompile(`[^\w]`)
+
+// stationOf finds the station of a district name in HKO report. Stations
+// not yet known are identified by their names.
+func stationOf(district string) (id string, name I18nName) {
+	if station, ok := LookupStationByAlias(district); ok {
+		return station.ID, station.Name
 	}
-	return
+	return normalizeStationName(district), I18nName{En: strings.TrimSpace(district)}
+}
+
+// signed decimal number in HKO report (e.g. "18", "-1.5", "minus 2")
+const reSignedNumber = `((?:minus\s+|[-−]\s*)?\d+(?:\.\d+)?)`
+
+var reDegree = regexp.MustCompile(`^.*?` + reSignedNumber + `\s+degrees?\b`)
+var reAirTemp = regexp.MustCompile(`Air temperature\s*:\s*` + reSignedNumber + `\s+degrees? Celsius`)
+var reHumidity = regexp.MustCompile(`Relative Humidity\s*:\s*` + reSignedNumber + `\s+per cent`)
+
+// parseSignedNumber parses signed decimal number matched by reSignedNumber
+func parseSignedNumber(str string) (float64, error) {
+	str = strings.Replace(str, "minus", "-", 1)
+	str = strings.Replace(str, "−", "-", 1)
+	str = strings.Join(strings.Fields(str), "")
+	return strconv.ParseFloat(str, 64)
 }
 
 // DecodeCurrentWeather decodes core content in the CurrentWeather.xml report
-func DecodeCurrentWeather(r io.Reader) (data *CurrentWeather, err error) {
+func DecodeCurrentWeather(r io.Reader, opts ...DecodeOption) (data *CurrentWeather, err error) {
+
+	// for better error reporting
+	parseErrors := make(ParseError, 0, 20)
 
 	// parse the content as RSS feed
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		parseErrors.fatal("body", "", "unable to read: %s", err)
+		return nil, parseErrors
+	}
 	parser := rss.Parser{}
-	feed, err := parser.Parse(r)
+	feed, err := parser.Parse(bytes.NewReader(body))
 	if err != nil {
+		parseErrors.fatal("body", rawExcerpt(body), "unable to parse RSS: %s", err)
+		return nil, parseErrors
+	}
+
+	if len(feed.Items) == 0 {
+		parseErrors.fatal("item", "", "no item in feed")
+		err = parseErrors
+		return
+	}
+	item := feed.Items[0]
+	if item.PubDateParsed == nil {
+		parseErrors.fatal("pubDate", item.PubDate, "unable to parse publish date")
+		err = parseErrors
 		return
 	}
 
 	// get description of the first item
-	desc := strings.NewReader(feed.Items[0].Description)
+	desc := strings.NewReader(item.Description)
 	doc, err := goquery.NewDocumentFromReader(desc)
 	if err != nil {
-		return
-	}
-
-	if feed.Items[0].PubDateParsed == nil {
-		err = fmt.Errorf("Failed to parse PubDate: %#v", feed.Items[0].PubDate)
-		return
+		parseErrors.fatal("description", rawExcerpt([]byte(item.Description)), "unable to parse HTML: %s", err)
+		return nil, parseErrors
 	}
 
 	// prepare the parse the Temperature table
-	reName := regexp.MustCompile(`[^\w]`)
-	reDegree := regexp.MustCompile(`^.*?(\d+) degree.+?$`)
 	data = &CurrentWeather{
-		PubDate: feed.Items[0].PubDateParsed.In(HKT),
-		Raw:     doc.Text(),
+		PubDate:  item.PubDateParsed.In(HKT),
+		Raw:      doc.Text(),
+		Stations: make(map[string]StationTemperature, 30),
 	}
-	distTempTyp := reflect.TypeOf(data.DistrictsTemperature)
-	distTempVal := reflect.ValueOf(&data.DistrictsTemperature).Elem()
-
-	// for better error reporting
-	parseErrors := make([]error, 0, 20)
 
 	// parse Temperature table
 	doc.Find("table tr").Each(func(i int, s *goquery.Selection) {
 		text1 := s.Find("td:nth-child(1)").Text()
 		text2 := s.Find("td:nth-child(2)").Text()
-		district := reName.ReplaceAllString(text1, "")
-		_, ok := distTempTyp.FieldByName(district)
+		district := strings.TrimSpace(text1)
+		field := fmt.Sprintf("Stations[%s]", district)
 
-		if !ok {
-			parseErrors = append(parseErrors, fmt.Errorf("[Warning] unknown district: %s", district))
-			return
-		}
-		if !reDegree.MatchString(text2) {
-			parseErrors = append(parseErrors, fmt.Errorf("[Error] unidentified degree string: %s (district: %s)", text2, district))
+		submatches := reDegree.FindStringSubmatch(text2)
+		if submatches == nil {
+			parseErrors.warn(field, text2, "unidentified degree string")
 			return
 		}
-
-		field := distTempVal.FieldByName(district)
-		submatches := reDegree.FindStringSubmatch(text2)
-		degree, err := strconv.ParseFloat(submatches[1], 64)
+		degree, err := parseSignedNumber(submatches[1])
 		if err != nil {
-			parseErrors = append(parseErrors, fmt.Errorf("[Error] unidentified degree number in string: %s (in %#v, district: %s)", submatches[1], text2, district))
+			parseErrors.warn(field, text2, "unidentified degree number: %s", submatches[1])
 			return
 		}
 
-		// set to the field
-		field.Set(reflect.ValueOf(Temperature(degree)))
+		// store the reading of the station
+		id, name := stationOf(district)
+		data.Stations[id] = StationTemperature{
+			ID:          id,
+			Name:        name,
+			Temperature: Temperature(degree),
+		}
 	})
 
 	// parse air temperature
 	descText := doc.Text()
-	reAirTemp := regexp.MustCompile(`Air temperature\s*:\s*(\d+)\s+(degree|degrees) Celsius`)
-	airTempStr := reAirTemp.FindStringSubmatch(descText)
-	airTemp, _ := strconv.ParseFloat(airTempStr[1], 64)
-	data.AirTemperature = Temperature(airTemp)
+	if submatches := reAirTemp.FindStringSubmatch(descText); submatches == nil {
+		parseErrors.warn("AirTemperature", "", "air temperature not found")
+	} else if airTemp, err := parseSignedNumber(submatches[1]); err != nil {
+		parseErrors.warn("AirTemperature", submatches[1], "unidentified air temperature")
+	} else {
+		data.AirTemperature = Temperature(airTemp)
+	}
 
 	// parse humidity
-	reHumidity := regexp.MustCompile(`Relative Humidity\s*:\s*(\d+)\s+per cent`)
-	humidityStr := reHumidity.FindStringSubmatch(descText)
-	humidity, _ := strconv.ParseFloat(humidityStr[1], 64)
-	data.RelativeHumidity = RelativeHumidity(humidity / 100)
+	if submatches := reHumidity.FindStringSubmatch(descText); submatches == nil {
+		parseErrors.warn("RelativeHumidity", "", "relative humidity not found")
+	} else if humidity, err := parseSignedNumber(submatches[1]); err != nil {
+		parseErrors.warn("RelativeHumidity", submatches[1], "unidentified relative humidity")
+	} else {
+		data.RelativeHumidity = RelativeHumidity(humidity / 100)
+	}
 
+	// flag suspect readings
+	data.checkQuality()
+
+	err = parseErrors.result(newDecodeOptions(opts))
+	if parseErrors.Fatal() {
+		data = nil
+	}
 	return
 }
@@ -1,7 +1,9 @@
 package hkodata_test
 
 import (
+	"encoding/json"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -33,79 +35,256 @@ func TestCurrentWeather(t *testing.T) {
 	if want, have := hkodata.RelativeHumidity(0.71), cw.RelativeHumidity; want != have {
 		t.Errorf("expected %#v, got %#v", want, have)
 	}
-	if want, have := hkodata.Temperature(18), cw.DistrictsTemperature.HongKongObservatory; want != have {
-		t.Errorf("expected %v, got %v", want, have)
+	expected := []struct {
+		id          string
+		district    string
+		temperature hkodata.Temperature
+	}{
+		{"hko", "HongKongObservatory", 18},
+		{"kp", "KingsPark", 17},
+		{"hks", "WongChukHang", 18},
+		{"tkl", "TaKwuLing", 16},
+		{"lfs", "LauFauShan", 17},
+		{"tpo", "TaiPo", 17},
+		{"sha", "ShaTin", 18},
+		{"tun", "TuenMun", 18},
+		{"jkb", "TseungKwanO", 17},
+		{"skg", "SaiKung", 18},
+		{"cch", "CheungChau", 17},
+		{"hka", "ChekLapKok", 19},
+		{"sek", "ShekKong", 18},
+		{"twn", "TsuenWanHoKoon", 16},
+		{"tw", "TsuenWanShingMunValley", 17},
+		{"hkp", "HongKongPark", 18},
+		{"skw", "ShauKeiWan", 18},
+		{"klt", "KowloonCity", 17},
+		{"hpv", "HappyValley", 19},
+		{"wts", "WongTaiSin", 18},
+		{"sty", "Stanley", 18},
+		{"ktg", "KwunTong", 18},
+		{"ssp", "ShamShuiPo", 18},
+		{"se1", "KaiTakRunwayPark", 19},
+		{"ylp", "YuenLongPark", 18},
 	}
-	if want, have := hkodata.Temperature(17), cw.DistrictsTemperature.KingsPark; want != have {
-		t.Errorf("expected %v, got %v", want, have)
+	if want, have := len(expected), len(cw.Stations); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
 	}
-	if want, have := hkodata.Temperature(18), cw.DistrictsTemperature.WongChukHang; want != have {
-		t.Errorf("expected %v, got %v", want, have)
+	districtsTemperature := cw.DistrictsTemperature()
+	for _, station := range expected {
+		if want, have := station.temperature, cw.Stations[station.id].Temperature; want != have {
+			t.Errorf("%s: expected %v, got %v", station.id, want, have)
+		}
+		if want, have := station.id, cw.Stations[station.id].ID; want != have {
+			t.Errorf("expected %#v, got %#v", want, have)
+		}
+		if want, have := hkodata.RegionName(station.id), cw.Stations[station.id].Name; want != have {
+			t.Errorf("expected %#v, got %#v", want, have)
+		}
+		if want, have := station.temperature, districtsTemperature[station.district]; want != have {
+			t.Errorf("%s: expected %v, got %v", station.district, want, have)
+		}
 	}
-	if want, have := hkodata.Temperature(16), cw.DistrictsTemperature.TaKwuLing; want != have {
-		t.Errorf("expected %v, got %v", want, have)
+
+	// check backward compatible JSON output
+	bytes, err := json.Marshal(cw)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
 	}
-	if want, have := hkodata.Temperature(17), cw.DistrictsTemperature.LauFauShan; want != have {
-		t.Errorf("expected %v, got %v", want, have)
+	var output struct {
+		DistrictsTemperature map[string]float64
 	}
-	if want, have := hkodata.Temperature(17), cw.DistrictsTemperature.TaiPo; want != have {
-		t.Errorf("expected %v, got %v", want, have)
+	json.Unmarshal(bytes, &output)
+	if want, have := 17.0, output.DistrictsTemperature["KingsPark"]; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
 	}
-	if want, have := hkodata.Temperature(18), cw.DistrictsTemperature.ShaTin; want != have {
-		t.Errorf("expected %v, got %v", want, have)
+}
+
+func TestCurrentWeather_newStation(t *testing.T) {
+	rss := `<?xml version="1.0" encoding="utf-8"?><rss version="2.0"><channel><title>Current Weather</title><item>
+<pubDate>Sat, 17 Dec 2016 13:02:00 GMT</pubDate><title>Bulletin updated at 21:02 HKT 17/12/2016</title>
+<description><![CDATA[
+Air temperature : 18 degrees Celsius<br/>
+Relative Humidity : 71 per cent<br/>
+<table>
+<tr><td><font size="-1">Hong Kong Observatory</font></td><td><font size="-1">18 degrees ;</font></td></tr>
+<tr><td><font size="-1">Some New Place</font></td><td><font size="-1">16 degrees .</font></td></tr>
+</table>
+]]></description></item></channel></rss>`
+
+	cw, err := hkodata.DecodeCurrentWeather(strings.NewReader(rss))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
 	}
-	if want, have := hkodata.Temperature(18), cw.DistrictsTemperature.TuenMun; want != have {
-		t.Errorf("expected %v, got %v", want, have)
+	station, ok := cw.Stations["somenewplace"]
+	if !ok {
+		t.Fatalf("expected station somenewplace, got %#v", cw.Stations)
 	}
-	if want, have := hkodata.Temperature(17), cw.DistrictsTemperature.TseungKwanO; want != have {
-		t.Errorf("expected %v, got %v", want, have)
+	if want, have := "Some New Place", station.Name.En; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
 	}
-	if want, have := hkodata.Temperature(18), cw.DistrictsTemperature.SaiKung; want != have {
-		t.Errorf("expected %v, got %v", want, have)
+	if want, have := hkodata.Temperature(16), station.Temperature; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
 	}
-	if want, have := hkodata.Temperature(17), cw.DistrictsTemperature.CheungChau; want != have {
-		t.Errorf("expected %v, got %v", want, have)
+	if want, have := hkodata.Temperature(16), cw.DistrictsTemperature()["SomeNewPlace"]; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
 	}
-	if want, have := hkodata.Temperature(19), cw.DistrictsTemperature.ChekLapKok; want != have {
-		t.Errorf("expected %v, got %v", want, have)
+}
+
+func TestCurrentWeather_coldSurge(t *testing.T) {
+	file, err := os.Open("./test/CurrentWeather.coldsurge.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
 	}
-	if want, have := hkodata.Temperature(18), cw.DistrictsTemperature.ShekKong; want != have {
-		t.Errorf("expected %v, got %v", want, have)
+	defer file.Close()
+
+	cw, err := hkodata.DecodeCurrentWeather(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
 	}
-	if want, have := hkodata.Temperature(16), cw.DistrictsTemperature.TsuenWanHoKoon; want != have {
-		t.Errorf("expected %v, got %v", want, have)
+
+	if want, have := hkodata.Temperature(3), cw.AirTemperature; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
 	}
-	if want, have := hkodata.Temperature(17), cw.DistrictsTemperature.TsuenWanShingMunValley; want != have {
-		t.Errorf("expected %v, got %v", want, have)
+	if want, have := hkodata.RelativeHumidity(0.54), cw.RelativeHumidity; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
 	}
-	if want, have := hkodata.Temperature(18), cw.DistrictsTemperature.HongKongPark; want != have {
-		t.Errorf("expected %v, got %v", want, have)
+
+	expected := map[string]hkodata.Temperature{
+		"tkl": 1,
+		"lfs": -1,
+		"tpo": 0,
+		"sek": -2,
+		"twn": -1,
+		"tms": -5.8,
 	}
-	if want, have := hkodata.Temperature(18), cw.DistrictsTemperature.ShauKeiWan; want != have {
-		t.Errorf("expected %v, got %v", want, have)
+	for id, temperature := range expected {
+		station, ok := cw.Stations[id]
+		if !ok {
+			t.Errorf("station %#v not found", id)
+			continue
+		}
+		if want, have := temperature, station.Temperature; want != have {
+			t.Errorf("%s: expected %v, got %v", id, want, have)
+		}
 	}
-	if want, have := hkodata.Temperature(17), cw.DistrictsTemperature.KowloonCity; want != have {
-		t.Errorf("expected %v, got %v", want, have)
+}
+
+func TestCurrentWeather_decimal(t *testing.T) {
+	file, err := os.Open("./test/CurrentWeather.decimal.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
 	}
-	if want, have := hkodata.Temperature(19), cw.DistrictsTemperature.HappyValley; want != have {
-		t.Errorf("expected %v, got %v", want, have)
+	defer file.Close()
+
+	cw, err := hkodata.DecodeCurrentWeather(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
 	}
-	if want, have := hkodata.Temperature(18), cw.DistrictsTemperature.WongTaiSin; want != have {
-		t.Errorf("expected %v, got %v", want, have)
+
+	if want, have := hkodata.Temperature(18.4), cw.AirTemperature; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := hkodata.RelativeHumidity(0.715), cw.RelativeHumidity; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	expected := map[string]hkodata.Temperature{
+		"hko": 18.4,
+		"kp":  17.2,
+		"hks": 18,
+		"tkl": 15.6,
+		"lfs": 17,
+	}
+	for id, temperature := range expected {
+		if want, have := temperature, cw.Stations[id].Temperature; want != have {
+			t.Errorf("%s: expected %v, got %v", id, want, have)
+		}
+	}
+}
+
+func TestCurrentWeather_unexpectedWording(t *testing.T) {
+	rss := `<?xml version="1.0" encoding="utf-8"?><rss version="2.0"><channel><title>Current Weather</title><item>
+<pubDate>Sat, 17 Dec 2016 13:02:00 GMT</pubDate><title>Bulletin updated at 21:02 HKT 17/12/2016</title>
+<description><![CDATA[
+Air temperature : not available<br/>
+<table>
+<tr><td><font size="-1">Hong Kong Observatory</font></td><td><font size="-1">18 degrees ;</font></td></tr>
+<tr><td><font size="-1">King's Park</font></td><td><font size="-1">N/A ;</font></td></tr>
+</table>
+]]></description></item></channel></rss>`
+
+	cw, err := hkodata.DecodeCurrentWeather(strings.NewReader(rss))
+	if cw == nil {
+		t.Fatalf("expected partial result, got nil")
 	}
-	if want, have := hkodata.Temperature(18), cw.DistrictsTemperature.Stanley; want != have {
-		t.Errorf("expected %v, got %v", want, have)
+	parseErrors, ok := err.(hkodata.ParseError)
+	if !ok {
+		t.Fatalf("expected hkodata.ParseError, got %#v", err)
 	}
-	if want, have := hkodata.Temperature(18), cw.DistrictsTemperature.KwunTong; want != have {
-		t.Errorf("expected %v, got %v", want, have)
+
+	// King's Park, air temperature and relative humidity
+	if want, have := 3, len(parseErrors); want != have {
+		t.Errorf("expected %#v, got %#v (%s)", want, have, parseErrors)
 	}
-	if want, have := hkodata.Temperature(18), cw.DistrictsTemperature.ShamShuiPo; want != have {
-		t.Errorf("expected %v, got %v", want, have)
+	if want, have := hkodata.Temperature(18), cw.Stations["hko"].Temperature; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
 	}
-	if want, have := hkodata.Temperature(19), cw.DistrictsTemperature.KaiTakRunwayPark; want != have {
-		t.Errorf("expected %v, got %v", want, have)
+	if _, ok := cw.Stations["kp"]; ok {
+		t.Errorf("expected no reading for kp")
+	}
+	if want, have := false, parseErrors.Fatal(); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
 	}
-	if want, have := hkodata.Temperature(18), cw.DistrictsTemperature.YuenLongPark; want != have {
-		t.Errorf("expected %v, got %v", want, have)
+	if want, have := 3, len(parseErrors.Warnings()); want != have {
+		t.Fatalf("expected %#v, got %#v", want, have)
+	}
+	if want, have := "N/A ;", parseErrors.Warnings()[0].Raw; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	// strict mode discards the partial result
+	cw, err = hkodata.DecodeCurrentWeather(strings.NewReader(rss), hkodata.Strict())
+	if cw != nil {
+		t.Errorf("expected nil, got %#v", cw)
+	}
+	if parseErrors, ok := err.(hkodata.ParseError); !ok {
+		t.Errorf("expected hkodata.ParseError, got %#v", err)
+	} else if want, have := true, parseErrors.Fatal(); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
+
+func TestCurrentWeather_noItem(t *testing.T) {
+	rss := `<?xml version="1.0" encoding="utf-8"?><rss version="2.0"><channel><title>Current Weather</title></channel></rss>`
+
+	cw, err := hkodata.DecodeCurrentWeather(strings.NewReader(rss))
+	if cw != nil {
+		t.Errorf("expected nil, got %#v", cw)
+	}
+	parseErrors, ok := err.(hkodata.ParseError)
+	if !ok {
+		t.Fatalf("expected hkodata.ParseError, got %#v", err)
+	}
+	if want, have := true, parseErrors.Fatal(); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
+
+func TestCurrentWeather_invalid(t *testing.T) {
+	cw, err := hkodata.DecodeCurrentWeather(strings.NewReader("some non-sense"))
+	if cw != nil {
+		t.Errorf("expected nil, got %#v", cw)
+	}
+	parseErrors, ok := err.(hkodata.ParseError)
+	if !ok {
+		t.Fatalf("expected hkodata.ParseError, got %#v", err)
+	}
+	if want, have := true, parseErrors.Fatal(); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if fieldErr, ok := parseErrors[0].(*hkodata.FieldError); !ok {
+		t.Errorf("expected *hkodata.FieldError, got %#v", parseErrors[0])
+	} else if want, have := "some non-sense", fieldErr.Raw; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
 	}
 }
@@ -0,0 +1,181 @@
+package hkodata
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Severity represents how serious an error in parsing is
+type Severity int
+
+const (
+	// SeverityWarning represents error that only affects a field.
+	// The rest of the decoded data is still usable.
+	SeverityWarning Severity = iota
+
+	// SeverityFatal represents error that makes the decoded data
+	// unusable as a whole.
+	SeverityFatal
+)
+
+// String implements fmt.Stringer
+func (severity Severity) String() string {
+	if severity == SeverityFatal {
+		return "fatal"
+	}
+	return "warning"
+}
+
+// MarshalJSON implements json.Marshaler
+func (severity Severity) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(severity.String())), nil
+}
+
+// FieldError represents error in parsing a field of HKO data
+type FieldError struct {
+	Severity Severity
+	Field    string
+	Raw      string
+	Err      error
+}
+
+// Error implements error interface
+func (err *FieldError) Error() string {
+	if err.Raw == "" {
+		return fmt.Sprintf("[%s] %s: %s", err.Severity, err.Field, err.Err)
+	}
+	return fmt.Sprintf("[%s] %s: %s (raw: %#v)", err.Severity, err.Field, err.Err, err.Raw)
+}
+
+// MarshalJSON implements json.Marshaler
+func (err *FieldError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Severity Severity `json:"severity"`
+		Field    string   `json:"field"`
+		Raw      string   `json:"raw"`
+		Message  string   `json:"message"`
+	}{
+		Severity: err.Severity,
+		Field:    err.Field,
+		Raw:      err.Raw,
+		Message:  err.Err.Error(),
+	})
+}
+
+// ParseError contains all error in parsing
+type ParseError []error
+
+// Error implements error interface
+func (errs ParseError) Error() (msg string) {
+	if len(errs) == 0 {
+		return ""
+	}
+
+	msg = "ParseError:\n"
+	for _, err := range errs {
+		msg += err.Error() + "\n"
+	}
+	return
+}
+
+// Fatal tells if there is any fatal error
+func (errs ParseError) Fatal() bool {
+	for _, err := range errs {
+		if fieldErr, ok := err.(*FieldError); !ok || fieldErr.Severity == SeverityFatal {
+			return true
+		}
+	}
+	return false
+}
+
+// Warnings returns all the errors of warning severity
+func (errs ParseError) Warnings() []*FieldError {
+	warnings := make([]*FieldError, 0, len(errs))
+	for _, err := range errs {
+		if fieldErr, ok := err.(*FieldError); ok && fieldErr.Severity == SeverityWarning {
+			warnings = append(warnings, fieldErr)
+		}
+	}
+	return warnings
+}
+
+// warn appends a warning of a field
+func (errs *ParseError) warn(field, raw string, format string, args ...interface{}) {
+	*errs = append(*errs, &FieldError{
+		Severity: SeverityWarning,
+		Field:    field,
+		Raw:      raw,
+		Err:      fmt.Errorf(format, args...),
+	})
+}
+
+// fatal appends a fatal error of a field
+func (errs *ParseError) fatal(field, raw string, format string, args ...interface{}) {
+	*errs = append(*errs, &FieldError{
+		Severity: SeverityFatal,
+		Field:    field,
+		Raw:      raw,
+		Err:      fmt.Errorf(format, args...),
+	})
+}
+
+// maxRawLen is the maximum length of raw input kept in FieldError
+const maxRawLen = 200
+
+// rawExcerpt returns the beginning of raw input for error reporting
+func rawExcerpt(b []byte) string {
+	if len(b) <= maxRawLen {
+		return string(b)
+	}
+	end := maxRawLen
+	for end > 0 && !utf8.RuneStart(b[end]) {
+		end--
+	}
+	return string(b[:end]) + "..."
+}
+
+// DecodeOption configures the behaviour of decoders
+type DecodeOption func(*decodeOptions)
+
+type decodeOptions struct {
+	strict bool
+}
+
+// Strict makes decoders treat every warning as fatal error
+func Strict() DecodeOption {
+	return func(options *decodeOptions) {
+		options.strict = true
+	}
+}
+
+// Lenient makes decoders return data with warnings (default)
+func Lenient() DecodeOption {
+	return func(options *decodeOptions) {
+		options.strict = false
+	}
+}
+
+func newDecodeOptions(opts []DecodeOption) (options decodeOptions) {
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return
+}
+
+// result returns the collected errors as error, or nil if there is none.
+// In strict mode, all warnings are promoted to fatal errors.
+func (errs ParseError) result(options decodeOptions) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	if options.strict {
+		for _, err := range errs {
+			if fieldErr, ok := err.(*FieldError); ok {
+				fieldErr.Severity = SeverityFatal
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,179 @@
+package hkodata
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// LapseRate is the standard lapse rate of air temperature
+// (in degree celcius per metre)
+const LapseRate = 0.0065
+
+// EstimateOption configures the estimation of readings at a point
+type EstimateOption func(*estimateOptions)
+
+type estimateOptions struct {
+	elevation   *float64
+	maxStations int
+	maxDistance float64
+}
+
+// AtElevation sets the elevation (in metres above mean sea level) of
+// the point to estimate. Temperature of stations are corrected to the
+// elevation by LapseRate. Without it, no correction is done.
+func AtElevation(elevation float64) EstimateOption {
+	return func(options *estimateOptions) {
+		options.elevation = &elevation
+	}
+}
+
+// MaxStations sets the maximum number of stations to estimate a
+// reading from (default 5)
+func MaxStations(n int) EstimateOption {
+	return func(options *estimateOptions) {
+		options.maxStations = n
+	}
+}
+
+// MaxDistance sets the maximum distance (in km) of stations to estimate
+// a reading from (default 20)
+func MaxDistance(km float64) EstimateOption {
+	return func(options *estimateOptions) {
+		options.maxDistance = km
+	}
+}
+
+// EstimateSource is a station contributing to an Estimate
+type EstimateSource struct {
+	ShortName         string
+	Name              I18nName
+	Distance          float64           // in km
+	Elevation         float64           // in metres
+	Temperature       *Temperature      `json:",omitempty"` // corrected to the elevation of the point
+	TemperatureWeight float64           `json:",omitempty"`
+	RelativeHumidity  *RelativeHumidity `json:",omitempty"`
+	HumidityWeight    float64           `json:",omitempty"`
+}
+
+// Estimate contains readings at a point estimated from the readings of
+// stations around
+type Estimate struct {
+	PubDate          time.Time
+	Latitude         float64
+	Longitude        float64
+	Elevation        *float64          `json:",omitempty"`
+	Temperature      *Temperature      `json:",omitempty"`
+	RelativeHumidity *RelativeHumidity `json:",omitempty"`
+	Sources          []EstimateSource
+}
+
+// Expires implements Expirer interface
+func (estimate Estimate) Expires() time.Time {
+	return estimate.PubDate.Add(10 * time.Minute)
+}
+
+// minDistance is the distance (in km) below which a station is
+// considered to be at the point, to avoid infinite weight
+const minDistance = 0.01
+
+// idwWeight returns the inverse distance weight (power 2) of a distance
+func idwWeight(distance float64) float64 {
+	return 1 / math.Pow(math.Max(distance, minDistance), 2)
+}
+
+// Estimate estimates the temperature and relative humidity at a point by
+// inverse distance weighting of the nearest stations having the readings.
+// Readings are nil if no station within range has them.
+func (regions Regions) Estimate(lat, lon float64, opts ...EstimateOption) *Estimate {
+	options := estimateOptions{
+		maxStations: 5,
+		maxDistance: 20,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	estimate := &Estimate{
+		PubDate:   regions.PubDate,
+		Latitude:  lat,
+		Longitude: lon,
+		Elevation: options.elevation,
+		Sources:   make([]EstimateSource, 0, options.maxStations),
+	}
+
+	// find the stations within range, ordered by distance
+	sources := make([]EstimateSource, 0, len(regions.Regions))
+	for _, region := range regions.Regions {
+		region = region.Trusted()
+		station, ok := stations[region.ShortName]
+		if !ok {
+			continue
+		}
+		source := EstimateSource{
+			ShortName:        region.ShortName,
+			Name:             region.Name,
+			Distance:         station.DistanceTo(lat, lon),
+			Elevation:        station.Elevation,
+			RelativeHumidity: region.RelativeHumidity,
+		}
+		if source.Distance > options.maxDistance {
+			continue
+		}
+		if region.CurrentTemp != nil {
+			temp := *region.CurrentTemp
+			if options.elevation != nil {
+				temp += Temperature(LapseRate * (station.Elevation - *options.elevation))
+			}
+			source.Temperature = &temp
+		}
+		sources = append(sources, source)
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i].Distance < sources[j].Distance
+	})
+
+	// weight the nearest stations of each reading
+	var tempSum, tempWeights, rhSum, rhWeights float64
+	var tempCount, rhCount int
+	for i := range sources {
+		source := &sources[i]
+		if source.Temperature != nil && tempCount < options.maxStations {
+			source.TemperatureWeight = idwWeight(source.Distance)
+			tempSum += float64(*source.Temperature) * source.TemperatureWeight
+			tempWeights += source.TemperatureWeight
+			tempCount++
+		}
+		if source.RelativeHumidity != nil && rhCount < options.maxStations {
+			source.HumidityWeight = idwWeight(source.Distance)
+			rhSum += float64(*source.RelativeHumidity) * source.HumidityWeight
+			rhWeights += source.HumidityWeight
+			rhCount++
+		}
+	}
+	if tempCount > 0 {
+		estimate.Temperature = NewTemperature(tempSum / tempWeights)
+	}
+	if rhCount > 0 {
+		estimate.RelativeHumidity = NewRelativeHumidity(rhSum / rhWeights)
+	}
+
+	// list the contributing stations with normalized weights
+	for _, source := range sources {
+		if source.TemperatureWeight == 0 && source.HumidityWeight == 0 {
+			continue
+		}
+		if source.TemperatureWeight > 0 {
+			source.TemperatureWeight /= tempWeights
+		} else {
+			source.Temperature = nil
+		}
+		if source.HumidityWeight > 0 {
+			source.HumidityWeight /= rhWeights
+		} else {
+			source.RelativeHumidity = nil
+		}
+		estimate.Sources = append(estimate.Sources, source)
+	}
+	return estimate
+}
@@ -0,0 +1,102 @@
+package hkodata_test
+
+import (
+	"math"
+	"os"
+	"testing"
+
+	"github.com/yookoala/weatherhk/hkodata"
+)
+
+func TestRegions_Estimate(t *testing.T) {
+	file, err := os.Open("./test/region_json.201612191037.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	defer file.Close()
+	regions, err := hkodata.DecodeRegionJSON(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	// at the Observatory, the estimate is dominated by its own reading
+	estimate := regions.Estimate(22.3019, 114.1742)
+	if want, have := regions.PubDate, estimate.PubDate; !want.Equal(have) {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+	if estimate.Temperature == nil {
+		t.Fatalf("expected temperature, got nil")
+	}
+	if want, have := 20.8, float64(*estimate.Temperature); math.Abs(want-have) > 0.05 {
+		t.Errorf("expected about %v, got %v", want, have)
+	}
+	if estimate.RelativeHumidity == nil {
+		t.Fatalf("expected relative humidity, got nil")
+	}
+	if want, have := 0.74, float64(*estimate.RelativeHumidity); math.Abs(want-have) > 0.005 {
+		t.Errorf("expected about %v, got %v", want, have)
+	}
+	if want, have := "hko", estimate.Sources[0].ShortName; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	// normalized weights
+	var tempWeights, rhWeights float64
+	for _, source := range estimate.Sources {
+		tempWeights += source.TemperatureWeight
+		rhWeights += source.HumidityWeight
+	}
+	if math.Abs(tempWeights-1) > 1e-9 || math.Abs(rhWeights-1) > 1e-9 {
+		t.Errorf("expected weights sum to 1, got %v and %v", tempWeights, rhWeights)
+	}
+
+	// far away from any station
+	estimate = regions.Estimate(0, 0)
+	if estimate.Temperature != nil {
+		t.Errorf("expected nil, got %#v", *estimate.Temperature)
+	}
+	if want, have := 0, len(estimate.Sources); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
+
+func TestRegions_Estimate_lapseRate(t *testing.T) {
+	regions := hkodata.Regions{
+		Regions: []hkodata.Region{
+			{ShortName: "tms", CurrentTemp: hkodata.NewTemperature(10)},
+		},
+	}
+	tms, _ := hkodata.LookupStation("tms")
+
+	atStation := regions.Estimate(tms.Latitude, tms.Longitude, hkodata.AtElevation(tms.Elevation))
+	if want, have := 10.0, float64(*atStation.Temperature); math.Abs(want-have) > 1e-9 {
+		t.Errorf("expected %v, got %v", want, have)
+	}
+
+	atSeaLevel := regions.Estimate(tms.Latitude, tms.Longitude, hkodata.AtElevation(0))
+	if want, have := 10+tms.Elevation*hkodata.LapseRate, float64(*atSeaLevel.Temperature); math.Abs(want-have) > 1e-9 {
+		t.Errorf("expected %v, got %v", want, have)
+	}
+
+	uncorrected := regions.Estimate(tms.Latitude, tms.Longitude)
+	if want, have := 10.0, float64(*uncorrected.Temperature); math.Abs(want-have) > 1e-9 {
+		t.Errorf("expected %v, got %v", want, have)
+	}
+}
+
+func TestRegions_Estimate_maxStations(t *testing.T) {
+	regions := hkodata.Regions{
+		Regions: []hkodata.Region{
+			{ShortName: "hko", CurrentTemp: hkodata.NewTemperature(20)},
+			{ShortName: "kp", CurrentTemp: hkodata.NewTemperature(30)},
+		},
+	}
+	hko, _ := hkodata.LookupStation("hko")
+	estimate := regions.Estimate(hko.Latitude+0.001, hko.Longitude, hkodata.MaxStations(1))
+	if want, have := 20.0, float64(*estimate.Temperature); want != have {
+		t.Errorf("expected %v, got %v", want, have)
+	}
+	if want, have := 1, len(estimate.Sources); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
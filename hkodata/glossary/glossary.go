@@ -0,0 +1,132 @@
+// Package glossary provides offline lookup of the weather glossary
+// (Wxword) terms referenced in HKO forecast text.
+//
+// The full HKO glossary is not bundled. Definitions are available only
+// for the terms seen in the sample forecasts under hkodata/test (0245,
+// 0249, 0250, 0264, 0277, 0285 and 0469). Other terms have no definition
+// offline; use TermOf to get at least their names from the forecast text
+// referencing them.
+package glossary
+
+import "github.com/yookoala/weatherhk/hkodata"
+
+// Term represents a term in HKO weather glossary
+type Term struct {
+	Code       string
+	Name       hkodata.I18nName
+	Definition hkodata.I18nName
+}
+
+var terms map[string]Term
+
+// LookupTerm finds a bundled glossary term by its HKO Wxword code
+// (e.g. "0285")
+func LookupTerm(code string) (term Term, ok bool) {
+	term, ok = terms[code]
+	return
+}
+
+// TermOf returns the glossary term referenced in the forecast text. For
+// terms not bundled, it returns the code and the name linked in the text
+// without definition. The name is in Chinese, as the forecast text of
+// HKO homepage bundle is.
+func TermOf(text hkodata.ForecastText, ref hkodata.GlossaryReference) Term {
+	if term, ok := LookupTerm(ref.Code); ok {
+		return term
+	}
+	return Term{
+		Code: ref.Code,
+		Name: hkodata.I18nName{Zh: text.Linked(ref)},
+	}
+}
+
+// Terms returns all the glossary terms bundled
+func Terms() []Term {
+	list := make([]Term, 0, len(terms))
+	for _, term := range terms {
+		list = append(list, term)
+	}
+	return list
+}
+
+func init() {
+	terms = map[string]Term{
+		"0245": {
+			Code: "0245",
+			Name: hkodata.I18nName{
+				Zh: "多雲",
+				En: "Cloudy",
+			},
+			Definition: hkodata.I18nName{
+				Zh: "天空大部分時間被雲層遮蓋，陽光甚少。",
+				En: "The sky is mostly covered by cloud with little sunshine.",
+			},
+		},
+		"0249": {
+			Code: "0249",
+			Name: hkodata.I18nName{
+				Zh: "部分時間有陽光",
+				En: "Sunny intervals",
+			},
+			Definition: hkodata.I18nName{
+				Zh: "有相當時間有陽光，但間中被雲層遮蓋。",
+				En: "Sunshine for a fair proportion of the time, interrupted by cloud at times.",
+			},
+		},
+		"0250": {
+			Code: "0250",
+			Name: hkodata.I18nName{
+				Zh: "短暫時間有陽光",
+				En: "Sunny periods",
+			},
+			Definition: hkodata.I18nName{
+				Zh: "只有短暫時間有陽光，其餘時間大致多雲。",
+				En: "Sunshine for brief periods only, with cloudy skies for most of the time.",
+			},
+		},
+		"0264": {
+			Code: "0264",
+			Name: hkodata.I18nName{
+				Zh: "有幾陣雨",
+				En: "A few showers",
+			},
+			Definition: hkodata.I18nName{
+				Zh: "預料會有數陣驟雨，驟雨之間會有間斷。",
+				En: "Several showers are expected, with breaks in between.",
+			},
+		},
+		"0277": {
+			Code: "0277",
+			Name: hkodata.I18nName{
+				Zh: "季候風",
+				En: "Monsoon",
+			},
+			Definition: hkodata.I18nName{
+				Zh: "隨季節轉變的盛行風。香港冬季主要受東北季候風影響，夏季則主要受西南季候風影響。",
+				En: "Prevailing wind that changes with the seasons. Hong Kong is mainly affected by the northeast monsoon in winter and the southwest monsoon in summer.",
+			},
+		},
+		"0285": {
+			Code: "0285",
+			Name: hkodata.I18nName{
+				Zh: "和緩",
+				En: "Moderate",
+			},
+			Definition: hkodata.I18nName{
+				Zh: "風力達蒲福氏風級3至4級，風速每小時12至28公里。",
+				En: "Wind of force 3 to 4 on the Beaufort scale, with speed of 12 to 28 km/h.",
+			},
+		},
+		"0469": {
+			Code: "0469",
+			Name: hkodata.I18nName{
+				Zh: "一兩陣雨",
+				En: "One or two showers",
+			},
+			Definition: hkodata.I18nName{
+				Zh: "預料只有一至兩陣為時短暫的驟雨。",
+				En: "Only one or two brief showers are expected.",
+			},
+		},
+	}
+}
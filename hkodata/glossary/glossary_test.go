@@ -0,0 +1,115 @@
+package glossary_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/yookoala/weatherhk/hkodata"
+	"github.com/yookoala/weatherhk/hkodata/glossary"
+)
+
+func TestLookupTerm(t *testing.T) {
+	term, ok := glossary.LookupTerm("0285")
+	if !ok {
+		t.Fatalf("expected term 0285 to be found")
+	}
+	if want, have := "0285", term.Code; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := "和緩", term.Name.Zh; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := "Moderate", term.Name.En; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	if _, ok := glossary.LookupTerm("non-exists"); ok {
+		t.Errorf("expected term not found")
+	}
+}
+
+func TestTerms(t *testing.T) {
+	for _, term := range glossary.Terms() {
+		if found, ok := glossary.LookupTerm(term.Code); !ok || found != term {
+			t.Errorf("term %#v is not keyed by its code", term.Code)
+		}
+		if term.Name.Zh == "" || term.Name.En == "" {
+			t.Errorf("name of term %#v is incomplete", term.Code)
+		}
+		if term.Definition.Zh == "" || term.Definition.En == "" {
+			t.Errorf("definition of term %#v is incomplete", term.Code)
+		}
+	}
+}
+
+func TestLookupTerm_localForecast(t *testing.T) {
+	file, err := os.Open("../test/one_json_uc.201612191917.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	defer file.Close()
+
+	one, err := hkodata.DecodeOneJSON(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	forecast, err := one.LocalForecast()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	// all terms referenced in the forecast should be bundled
+	texts := []hkodata.ForecastText{
+		forecast.GeneralSituation,
+		forecast.ForecastDesc,
+		forecast.OutlookContent,
+	}
+	for _, text := range texts {
+		for _, ref := range text.Terms {
+			if _, ok := glossary.LookupTerm(ref.Code); !ok {
+				t.Errorf("term %#v not found", ref.Code)
+			}
+		}
+	}
+}
+
+func TestTermOf(t *testing.T) {
+	text, err := hkodata.ParseForecastText(`吹<a href="javascript:Wxword('0285')">和緩</a>東風，<a href="javascript:Wxword('9999')">未知用語</a>。`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if want, have := 2, len(text.Terms); want != have {
+		t.Fatalf("expected %#v, got %#v", want, have)
+	}
+
+	// bundled term
+	if want, have := "Moderate", glossary.TermOf(text, text.Terms[0]).Name.En; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	// term not bundled, only named as linked in the text
+	term := glossary.TermOf(text, text.Terms[1])
+	if want, have := "9999", term.Code; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := "未知用語", term.Name.Zh; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := (hkodata.I18nName{}), term.Definition; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
+
+func TestLookupTerm_beaufort(t *testing.T) {
+	term, ok := glossary.LookupTerm("0285")
+	if !ok {
+		t.Fatalf("expected term 0285 to be found")
+	}
+
+	// the term should agree with the wind force descriptions
+	for _, speed := range []hkodata.Speed{12, 28} {
+		if want, have := term.Name, speed.Beaufort().Description(); want != have {
+			t.Errorf("speed %.0f km/h: expected %#v, got %#v", float64(speed), want, have)
+		}
+	}
+}
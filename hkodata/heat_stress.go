@@ -0,0 +1,167 @@
+package hkodata
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// HeatStressLevel represents the advisory level of heat stress at work,
+// following the thresholds of the Heat Stress at Work Warning of the
+// Labour Department
+type HeatStressLevel int
+
+const (
+	// HeatStressNone represents WBGT below 30 degree celcius
+	HeatStressNone HeatStressLevel = iota
+
+	// HeatStressAmber represents WBGT 30 to below 32 degree celcius
+	HeatStressAmber
+
+	// HeatStressRed represents WBGT 32 to below 34 degree celcius
+	HeatStressRed
+
+	// HeatStressBlack represents WBGT 34 degree celcius or above
+	HeatStressBlack
+)
+
+var heatStressLevelNames = map[HeatStressLevel]I18nName{
+	HeatStressNone:  {Zh: "無", En: "none"},
+	HeatStressAmber: {Zh: "黃色", En: "amber"},
+	HeatStressRed:   {Zh: "紅色", En: "red"},
+	HeatStressBlack: {Zh: "黑色", En: "black"},
+}
+
+// HeatStressLevelOf returns the advisory level of the WBGT
+func HeatStressLevelOf(wbgt Temperature) HeatStressLevel {
+	switch {
+	case wbgt >= 34:
+		return HeatStressBlack
+	case wbgt >= 32:
+		return HeatStressRed
+	case wbgt >= 30:
+		return HeatStressAmber
+	}
+	return HeatStressNone
+}
+
+// Name returns the name of the level
+func (level HeatStressLevel) Name() I18nName {
+	return heatStressLevelNames[level]
+}
+
+// String implements fmt.Stringer
+func (level HeatStressLevel) String() string {
+	return heatStressLevelNames[level].En
+}
+
+// MarshalJSON implements json.Marshaler
+func (level HeatStressLevel) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(level.String())), nil
+}
+
+// maxSolarLoad is the maximum WBGT (in degree celcius) added for
+// exposure to sunlight
+const maxSolarLoad = 3.0
+
+// EstimateWBGT estimates the wet-bulb globe temperature of the
+// temperature and relative humidity. The shade WBGT is approximated by
+// the formula of the Australian Bureau of Meteorology. If an UV index
+// is given, a solar load of 0.3 degree per unit of UV index (up to 3
+// degrees) is added as a rough heuristic for exposure to sunlight.
+func EstimateWBGT(temp Temperature, rh RelativeHumidity, uv *UVIndex) (wbgt Temperature, solarAdjusted bool) {
+	wbgt = Temperature(0.567*float64(temp) + 0.393*vapourPressure(temp, rh) + 3.94)
+	if uv != nil && *uv > 0 {
+		wbgt += Temperature(math.Min(0.3*float64(*uv), maxSolarLoad))
+		solarAdjusted = true
+	}
+	return
+}
+
+// HeatStress contains the estimated heat stress of a location
+type HeatStress struct {
+	WBGT          Temperature
+	Level         HeatStressLevel
+	SolarAdjusted bool // if solar load by UV index is included
+}
+
+// NewHeatStress estimates the heat stress of the readings
+func NewHeatStress(temp Temperature, rh RelativeHumidity, uv *UVIndex) HeatStress {
+	wbgt, solarAdjusted := EstimateWBGT(temp, rh, uv)
+	return HeatStress{
+		WBGT:          wbgt,
+		Level:         HeatStressLevelOf(wbgt),
+		SolarAdjusted: solarAdjusted,
+	}
+}
+
+// StationHeatStress contains the estimated heat stress at a station
+type StationHeatStress struct {
+	ShortName        string
+	Name             I18nName
+	Temperature      Temperature
+	RelativeHumidity RelativeHumidity
+	HumidityFallback bool // if relative humidity at HKO is used
+	HeatStress
+}
+
+// HeatStressReport contains the estimated heat stress at stations
+type HeatStressReport struct {
+	PubDate  time.Time
+	UVIndex  *UVIndex        `json:",omitempty"`
+	MaxLevel HeatStressLevel // the highest level of all stations
+	Stations []StationHeatStress
+}
+
+// Expires implements Expirer interface
+func (report HeatStressReport) Expires() time.Time {
+	return report.PubDate.Add(10 * time.Minute)
+}
+
+// NewHeatStressReport estimates the heat stress at stations from the
+// Regions and the CurrentWeather data, either of which can be nil.
+// Stations without relative humidity reading use the one reported in
+// CurrentWeather. UV reading, if given, is used as solar load of all
+// stations.
+func NewHeatStressReport(regions *Regions, currentWeather *CurrentWeather, uv *UVReading) *HeatStressReport {
+	report := &HeatStressReport{
+		Stations: make([]StationHeatStress, 0, 50),
+	}
+	if uv != nil {
+		report.UVIndex = uv.Index
+	}
+
+	readings, pubDate := mergeReadings(regions, currentWeather)
+	report.PubDate = pubDate
+
+	for id, region := range readings {
+		if region.CurrentTemp == nil {
+			continue
+		}
+		station := StationHeatStress{
+			ShortName:   id,
+			Name:        region.Name,
+			Temperature: *region.CurrentTemp,
+		}
+		switch {
+		case region.RelativeHumidity != nil:
+			station.RelativeHumidity = *region.RelativeHumidity
+		case currentWeather != nil && currentWeather.RelativeHumidity > 0 &&
+			len(currentWeather.Quality["RelativeHumidity"]) == 0:
+			station.RelativeHumidity = currentWeather.RelativeHumidity
+			station.HumidityFallback = true
+		default:
+			continue
+		}
+		station.HeatStress = NewHeatStress(station.Temperature, station.RelativeHumidity, report.UVIndex)
+		if station.Level > report.MaxLevel {
+			report.MaxLevel = station.Level
+		}
+		report.Stations = append(report.Stations, station)
+	}
+	sort.Slice(report.Stations, func(i, j int) bool {
+		return report.Stations[i].ShortName < report.Stations[j].ShortName
+	})
+	return report
+}
@@ -0,0 +1,110 @@
+package hkodata_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/yookoala/weatherhk/hkodata"
+)
+
+func TestEstimateWBGT(t *testing.T) {
+	// hot and humid summer afternoon
+	wbgt, solarAdjusted := hkodata.EstimateWBGT(33, .7, nil)
+	if want, have := 36.4, float64(wbgt); math.Abs(want-have) > 0.1 {
+		t.Errorf("expected about %v, got %v", want, have)
+	}
+	if want, have := false, solarAdjusted; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	// solar load is capped
+	sunny, solarAdjusted := hkodata.EstimateWBGT(33, .7, hkodata.NewUVIndex(12))
+	if want, have := float64(wbgt)+3, float64(sunny); math.Abs(want-have) > 1e-9 {
+		t.Errorf("expected %v, got %v", want, have)
+	}
+	if want, have := true, solarAdjusted; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
+
+func TestHeatStressLevelOf(t *testing.T) {
+	tests := []struct {
+		wbgt  hkodata.Temperature
+		level hkodata.HeatStressLevel
+	}{
+		{25, hkodata.HeatStressNone},
+		{29.9, hkodata.HeatStressNone},
+		{30, hkodata.HeatStressAmber},
+		{32, hkodata.HeatStressRed},
+		{33.9, hkodata.HeatStressRed},
+		{34, hkodata.HeatStressBlack},
+	}
+	for _, test := range tests {
+		if want, have := test.level, hkodata.HeatStressLevelOf(test.wbgt); want != have {
+			t.Errorf("%v: expected %s, got %s", test.wbgt, want, have)
+		}
+	}
+	if want, have := "紅色", hkodata.HeatStressRed.Name().Zh; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
+
+func TestNewHeatStressReport(t *testing.T) {
+	pubDate := time.Date(2016, time.July, 19, 14, 0, 0, 0, hkodata.HKT)
+	regions := &hkodata.Regions{
+		PubDate: pubDate,
+		Regions: []hkodata.Region{
+			{ShortName: "hko", CurrentTemp: hkodata.NewTemperature(33), RelativeHumidity: hkodata.NewRelativeHumidity(.7)},
+			{ShortName: "hkp", CurrentTemp: hkodata.NewTemperature(34)},
+			{ShortName: "gi", WindSpeed: hkodata.NewSpeed(20)},
+		},
+	}
+	cw := &hkodata.CurrentWeather{
+		PubDate:          pubDate.Add(-time.Hour),
+		AirTemperature:   33,
+		RelativeHumidity: .65,
+		Stations: map[string]hkodata.StationTemperature{
+			"kp": {ID: "kp", Temperature: 32},
+		},
+	}
+	uv := &hkodata.UVReading{Index: hkodata.NewUVIndex(9)}
+
+	report := hkodata.NewHeatStressReport(regions, cw, uv)
+	if want, have := pubDate, report.PubDate; !want.Equal(have) {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+	if want, have := 3, len(report.Stations); want != have {
+		t.Fatalf("expected %#v, got %#v", want, have)
+	}
+
+	// ordered by short name
+	hko, hkp, kp := report.Stations[0], report.Stations[1], report.Stations[2]
+	if want, have := "hko", hko.ShortName; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := false, hko.HumidityFallback; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := true, hkp.HumidityFallback; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := hkodata.RelativeHumidity(.65), hkp.RelativeHumidity; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := hkodata.Temperature(32), kp.Temperature; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := true, hko.SolarAdjusted; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := hkodata.HeatStressBlack, report.MaxLevel; want != have {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+
+	bytes, _ := json.Marshal(hko.Level)
+	if want, have := `"black"`, string(bytes); want != have {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+}
@@ -0,0 +1,136 @@
+package hkodata
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// lightningColors are the colours of the time slots in the legend of
+// HKO lightning map, from grey (#808080) for the earliest to red
+// (#FF0000) for the latest
+var lightningColors = map[string]bool{
+	"#808080": true,
+	"#004080": true,
+	"#8000FF": true,
+	"#00FFFF": true,
+	"#DD6F00": true,
+	"#FF0000": true,
+}
+
+// LightningSlot is a 5-minute time slot in the legend of HKO lightning
+// map, which plots lightning of each slot in the past 30 minutes with its
+// own colour. HKO lists the slots whether or not there is lightning (e.g.
+// test/one_json_uc.201612191917.xml of a dry evening), so a slot is not
+// a record of lightning.
+//
+// The slot starts at Start (inclusive) and ends at End (exclusive). Age
+// is the minutes from End to the PubDate of the legend.
+type LightningSlot struct {
+	Start time.Time
+	End   time.Time
+	Color string
+	Age   int
+}
+
+// Lightning contains the legend of HKO lightning map. PubDate is the
+// end of the latest slot.
+type Lightning struct {
+	PubDate time.Time
+	Slots   []LightningSlot
+}
+
+// Expires implements Expirer interface
+// (lightning data are updated every 5 minutes)
+func (lightning Lightning) Expires() time.Time {
+	return lightning.PubDate.Add(5 * time.Minute)
+}
+
+// Summary summarizes the lightning recorded after the given time. The
+// legend is no evidence of lightning, so whether lightning is recorded
+// is left unknown.
+func (lightning Lightning) Summary(since time.Time) LightningSummary {
+	return LightningSummary{
+		PubDate: lightning.PubDate,
+		Since:   since,
+	}
+}
+
+// LightningSummary tells if lightning is recorded since a given time.
+// Recorded is nil if unknown, which is always the case with the HKO
+// homepage bundle as it has no record of lightning strikes.
+type LightningSummary struct {
+	PubDate  time.Time
+	Since    time.Time
+	Recorded *bool
+}
+
+// Expires implements Expirer interface
+// (lightning data are updated every 5 minutes)
+func (summary LightningSummary) Expires() time.Time {
+	return summary.PubDate.Add(5 * time.Minute)
+}
+
+// parseLightningSlot parses a lightning legend entry of HKO data
+// (e.g. date "19-Dec-2016" with time "18:40-18:44")
+func parseLightningSlot(entry OneJSONLightning) (slot LightningSlot, err error) {
+	times := strings.SplitN(entry.Time, "-", 2)
+	if len(times) != 2 {
+		err = fmt.Errorf("unidentified time slot: %#v", entry.Time)
+		return
+	}
+	date, err := time.ParseInLocation("2-Jan-2006", strings.TrimSpace(entry.Date), HKT)
+	if err != nil {
+		err = fmt.Errorf("unidentified date: %#v", entry.Date)
+		return
+	}
+	if slot.Start, err = parseClock(date, times[0]); err != nil {
+		return
+	}
+	if slot.End, err = parseClock(date, times[1]); err != nil {
+		return
+	}
+
+	// the slot end is the last minute included
+	slot.End = slot.End.Add(time.Minute)
+	if !slot.End.After(slot.Start) {
+		slot.End = slot.End.AddDate(0, 0, 1)
+	}
+
+	slot.Color = entry.Color
+	return
+}
+
+// Lightning parses the lightning_info section into Lightning
+func (one OneJSON) Lightning(opts ...DecodeOption) (lightning *Lightning, err error) {
+	parseErrors := make(ParseError, 0, 4)
+
+	lightning = &Lightning{
+		PubDate: one.PubDate,
+		Slots:   make([]LightningSlot, 0, len(one.LightningInfo)),
+	}
+	for i, entry := range one.LightningInfo {
+		field := fmt.Sprintf("lightning_info[%d]", i)
+		slot, err := parseLightningSlot(entry)
+		if err != nil {
+			parseErrors.warn(field, entry.Date+" "+entry.Time, "%s", err)
+			continue
+		}
+		if !lightningColors[strings.ToUpper(strings.TrimSpace(entry.Color))] {
+			parseErrors.warn(field+".color", entry.Color, "unknown lightning colour")
+		}
+		if slot.End.After(lightning.PubDate) {
+			lightning.PubDate = slot.End
+		}
+		lightning.Slots = append(lightning.Slots, slot)
+	}
+	for i := range lightning.Slots {
+		lightning.Slots[i].Age = int(lightning.PubDate.Sub(lightning.Slots[i].End) / time.Minute)
+	}
+
+	err = parseErrors.result(newDecodeOptions(opts))
+	if parseErrors.Fatal() {
+		lightning = nil
+	}
+	return
+}
@@ -0,0 +1,103 @@
+package hkodata_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/yookoala/weatherhk/hkodata"
+)
+
+func TestOneJSON_Lightning(t *testing.T) {
+	file, err := os.Open("./test/one_json_uc.201612191917.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	defer file.Close()
+
+	one, err := hkodata.DecodeOneJSON(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	lightning, err := one.Lightning()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if want, have := 6, len(lightning.Slots); want != have {
+		t.Fatalf("expected %#v, got %#v", want, have)
+	}
+	if want, have := time.Date(2016, time.December, 19, 19, 10, 0, 0, hkodata.HKT), lightning.PubDate; !want.Equal(have) {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+
+	first := lightning.Slots[0]
+	if want, have := time.Date(2016, time.December, 19, 18, 40, 0, 0, hkodata.HKT), first.Start; !want.Equal(have) {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+	if want, have := time.Date(2016, time.December, 19, 18, 45, 0, 0, hkodata.HKT), first.End; !want.Equal(have) {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+	if want, have := 25, first.Age; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := 0, lightning.Slots[5].Age; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	// the fixture is of a dry evening, the legend is no evidence of
+	// lightning recorded
+	summary := lightning.Summary(time.Date(2016, time.December, 19, 19, 0, 0, 0, hkodata.HKT))
+	if summary.Recorded != nil {
+		t.Errorf("expected nil, got %#v", *summary.Recorded)
+	}
+	if want, have := lightning.PubDate, summary.PubDate; !want.Equal(have) {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+}
+
+func TestOneJSON_Lightning_noLegend(t *testing.T) {
+	one := hkodata.OneJSON{
+		PubDate: time.Date(2016, time.December, 19, 19, 0, 0, 0, hkodata.HKT),
+	}
+	lightning, err := one.Lightning()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if want, have := 0, len(lightning.Slots); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if summary := lightning.Summary(one.PubDate.Add(-30 * time.Minute)); summary.Recorded != nil {
+		t.Errorf("expected nil, got %#v", *summary.Recorded)
+	}
+}
+
+func TestOneJSON_Lightning_parseError(t *testing.T) {
+	one := hkodata.OneJSON{
+		LightningInfo: []hkodata.OneJSONLightning{
+			{Date: "19-Dec-2016", Time: "23:55-23:59", Color: "#ff0000"},
+			{Date: "19-Dec-2016", Time: "some non-sense", Color: "#FF0000"},
+			{Date: "19-Dec-2016", Time: "18:45-18:49", Color: "#123456"},
+		},
+	}
+
+	lightning, err := one.Lightning()
+	if _, ok := err.(hkodata.ParseError); !ok {
+		t.Errorf("expected hkodata.ParseError, got %#v", err)
+	}
+	if want, have := 2, len(lightning.Slots); want != have {
+		t.Fatalf("expected %#v, got %#v", want, have)
+	}
+	if want, have := time.Date(2016, time.December, 20, 0, 0, 0, 0, hkodata.HKT), lightning.Slots[0].End; !want.Equal(have) {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+	if want, have := 0, lightning.Slots[0].Age; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := 310, lightning.Slots[1].Age; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := 2, len(err.(hkodata.ParseError).Warnings()); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
@@ -0,0 +1,187 @@
+package hkodata
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// GlossaryReference refers to a term in HKO weather glossary (Wxword)
+// within a text. Start and End are offsets in characters (runes) of
+// the referencing text.
+type GlossaryReference struct {
+	Code  string
+	Start int
+	End   int
+}
+
+// ForecastText contains plain text of a forecast with references to
+// glossary terms in it
+type ForecastText struct {
+	Text  string
+	Terms []GlossaryReference
+}
+
+// String implements fmt.Stringer
+func (text ForecastText) String() string {
+	return text.Text
+}
+
+// Linked returns the part of text linked by the reference, which is
+// usually the name of the term
+func (text ForecastText) Linked(ref GlossaryReference) string {
+	runes := []rune(text.Text)
+	if ref.Start < 0 || ref.End > len(runes) || ref.Start > ref.End {
+		return ""
+	}
+	return string(runes[ref.Start:ref.End])
+}
+
+var reWxword = regexp.MustCompile(`Wxword\(\s*'(\w+)'\s*\)`)
+
+// forecastTextBuilder builds ForecastText from HTML nodes
+type forecastTextBuilder struct {
+	buf   bytes.Buffer
+	terms []GlossaryReference
+}
+
+func (builder *forecastTextBuilder) offset() int {
+	return utf8.RuneCount(builder.buf.Bytes())
+}
+
+func (builder *forecastTextBuilder) walk(node *html.Node) {
+	switch node.Type {
+	case html.TextNode:
+		builder.buf.WriteString(node.Data)
+		return
+	case html.ElementNode:
+		switch node.DataAtom {
+		case atom.Br:
+			builder.buf.WriteString("\n")
+			return
+		case atom.A:
+			for _, attr := range node.Attr {
+				if attr.Key != "href" {
+					continue
+				}
+				if submatches := reWxword.FindStringSubmatch(attr.Val); submatches != nil {
+					ref := GlossaryReference{
+						Code:  submatches[1],
+						Start: builder.offset(),
+					}
+					builder.walkChildren(node)
+					ref.End = builder.offset()
+					builder.terms = append(builder.terms, ref)
+					return
+				}
+			}
+		}
+	}
+	builder.walkChildren(node)
+}
+
+func (builder *forecastTextBuilder) walkChildren(node *html.Node) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		builder.walk(child)
+	}
+}
+
+// ParseForecastText parses HTML forecast text of HKO into plain text
+// with the `javascript:Wxword('code')` links turned into references
+// of glossary terms
+func ParseForecastText(str string) (text ForecastText, err error) {
+	nodes, err := html.ParseFragment(strings.NewReader(str), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "div",
+		DataAtom: atom.Div,
+	})
+	if err != nil {
+		return
+	}
+
+	builder := &forecastTextBuilder{
+		terms: make([]GlossaryReference, 0, 8),
+	}
+	for _, node := range nodes {
+		builder.walk(node)
+	}
+	text.Text = builder.buf.String()
+	text.Terms = builder.terms
+	return
+}
+
+// LocalForecast contains the local weather forecast
+type LocalForecast struct {
+	PubDate           time.Time
+	GeneralSituation  ForecastText
+	TCInfo            ForecastText
+	FireDangerWarning ForecastText
+	ForecastPeriod    string
+	ForecastDesc      ForecastText
+	OutlookTitle      string
+	OutlookContent    ForecastText
+	Icons             []int
+}
+
+// Expires implements Expirer interface
+// (the bulletin is updated a few times a day without fixed schedule)
+func (forecast LocalForecast) Expires() time.Time {
+	return forecast.PubDate.Add(60 * time.Minute)
+}
+
+// LocalForecast parses the FLW section into LocalForecast
+func (one OneJSON) LocalForecast(opts ...DecodeOption) (forecast *LocalForecast, err error) {
+	flw := one.FLW
+	parseErrors := make(ParseError, 0, 8)
+
+	forecast = &LocalForecast{
+		ForecastPeriod: flw.ForecastPeriod,
+		OutlookTitle:   flw.OutlookTitle,
+		Icons:          make([]int, 0, 2),
+	}
+	if forecast.PubDate, err = time.ParseInLocation("200601021504", flw.BulletinDate+flw.BulletinTime, HKT); err != nil {
+		parseErrors.fatal("FLW.BulletinTime", flw.BulletinDate+flw.BulletinTime, "unable to parse bulletin time")
+	}
+
+	fields := []struct {
+		name string
+		str  string
+		text *ForecastText
+	}{
+		{"GeneralSituation", flw.GeneralSituation, &forecast.GeneralSituation},
+		{"TCInfo", flw.TCInfo, &forecast.TCInfo},
+		{"FireDangerWarning", flw.FireDangerWarning, &forecast.FireDangerWarning},
+		{"ForecastDesc", flw.ForecastDesc, &forecast.ForecastDesc},
+		{"OutlookContent", flw.OutlookContent, &forecast.OutlookContent},
+	}
+	for _, field := range fields {
+		if *field.text, err = ParseForecastText(field.str); err != nil {
+			parseErrors.warn("FLW."+field.name, field.str, "%s", err)
+		}
+	}
+
+	for i, iconStr := range []string{flw.Icon1, flw.Icon2} {
+		if iconStr == "" {
+			continue
+		}
+		icon, err := strconv.Atoi(iconStr)
+		if err != nil {
+			parseErrors.warn(fmt.Sprintf("FLW.Icon%d", i+1), iconStr, "unable to parse icon code")
+			continue
+		}
+		forecast.Icons = append(forecast.Icons, icon)
+	}
+
+	err = parseErrors.result(newDecodeOptions(opts))
+	if parseErrors.Fatal() {
+		forecast = nil
+	}
+	return
+}
@@ -0,0 +1,84 @@
+package hkodata_test
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	pretty "github.com/tonnerre/golang-pretty"
+	"github.com/yookoala/weatherhk/hkodata"
+)
+
+func TestParseForecastText(t *testing.T) {
+	text, err := hkodata.ParseForecastText(`晚間漸轉<a title="在新視窗顯示" href="javascript:Wxword('0245')">多雲</a>。<br/>吹<a href="javascript:Wxword('0285')">和緩</a>偏東風&amp;<b>清涼</b>。`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if want, have := "晚間漸轉多雲。\n吹和緩偏東風&清涼。", text.Text; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	expected := []hkodata.GlossaryReference{
+		{Code: "0245", Start: 4, End: 6},
+		{Code: "0285", Start: 9, End: 11},
+	}
+	if want, have := expected, text.Terms; !reflect.DeepEqual(want, have) {
+		t.Errorf("unexpected difference in terms (want != have)")
+		for _, desc := range pretty.Diff(want, have) {
+			t.Log("\tterms" + desc)
+		}
+	}
+	for i, name := range []string{"多雲", "和緩"} {
+		if want, have := name, text.Linked(expected[i]); want != have {
+			t.Errorf("expected %#v, got %#v", want, have)
+		}
+	}
+}
+
+func TestOneJSON_LocalForecast(t *testing.T) {
+	file, err := os.Open("./test/one_json_uc.201612191917.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	defer file.Close()
+
+	one, err := hkodata.DecodeOneJSON(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	forecast, err := one.LocalForecast()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if want, have := time.Date(2016, time.December, 19, 18, 45, 0, 0, hkodata.HKT), forecast.PubDate; !want.Equal(have) {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+	if want, have := "一股和緩的東北季候風正影響廣東沿岸。", forecast.GeneralSituation.Text; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	expected := []hkodata.GlossaryReference{
+		{Code: "0285", Start: 2, End: 4},
+		{Code: "0277", Start: 7, End: 10},
+	}
+	if want, have := expected, forecast.GeneralSituation.Terms; !reflect.DeepEqual(want, have) {
+		t.Errorf("unexpected difference in terms (want != have)")
+		for _, desc := range pretty.Diff(want, have) {
+			t.Log("\tterms" + desc)
+		}
+	}
+	if want, have := "星期三多雲及有幾陣雨。隨後一兩天部分時間有陽光，天氣稍涼。", forecast.OutlookContent.Text; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := 3, len(forecast.OutlookContent.Terms); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := "", forecast.TCInfo.Text; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := []int{76}, forecast.Icons; !reflect.DeepEqual(want, have) {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
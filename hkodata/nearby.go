@@ -0,0 +1,191 @@
+package hkodata
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// earthRadius is the mean radius of the earth in km
+const earthRadius = 6371.0
+
+// Distance returns the great-circle distance (in km) between 2 points
+// of the given latitudes and longitudes (in degrees)
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	// haversine formula
+	phi1, phi2 := lat1*math.Pi/180, lat2*math.Pi/180
+	dPhi := phi2 - phi1
+	dLambda := (lon2 - lon1) * math.Pi / 180
+	a := math.Pow(math.Sin(dPhi/2), 2) + math.Cos(phi1)*math.Cos(phi2)*math.Pow(math.Sin(dLambda/2), 2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
+
+// DistanceTo returns the distance (in km) from the station to a point
+func (station Station) DistanceTo(lat, lon float64) float64 {
+	return Distance(station.Latitude, station.Longitude, lat, lon)
+}
+
+// NearbyStation is a station with its distance to a point
+type NearbyStation struct {
+	Station
+	Distance float64 // in km
+}
+
+// NearestStations returns the n stations nearest to a point, ordered by
+// distance. All stations are returned if n <= 0.
+func NearestStations(lat, lon float64, n int) []NearbyStation {
+	list := make([]NearbyStation, 0, len(stations))
+	for _, station := range stations {
+		list = append(list, NearbyStation{
+			Station:  station,
+			Distance: station.DistanceTo(lat, lon),
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Distance < list[j].Distance
+	})
+	if n > 0 && n < len(list) {
+		list = list[:n]
+	}
+	return list
+}
+
+// NearbyRange is the maximum distance (in km) of stations to take
+// readings from for a point. It covers the whole territory from any point
+// within it, but not places far away.
+const NearbyRange = 50.0
+
+// NearbyRegion is the readings of a region with the distance of its
+// station to a point
+type NearbyRegion struct {
+	Region
+	Distance float64 // in km
+}
+
+// NearbySource identifies the station a reading is taken from
+type NearbySource struct {
+	ShortName string
+	Name      I18nName
+	Distance  float64 // in km
+}
+
+// NearbyConditions contains the latest readings around a point. Each
+// reading in Conditions is taken from the nearest station that has it,
+// as listed in Sources.
+type NearbyConditions struct {
+	PubDate    time.Time
+	Latitude   float64
+	Longitude  float64
+	Conditions Region
+	Sources    map[string]NearbySource // keyed by field name in Region
+	Stations   []NearbyRegion          // nearest stations with readings
+}
+
+// Expires implements Expirer interface
+func (nearby NearbyConditions) Expires() time.Time {
+	return nearby.PubDate.Add(10 * time.Minute)
+}
+
+// mergeReadings merges the readings of Regions and CurrentWeather, either
+// of which can be nil, by station. Suspect readings are ignored.
+// Temperature in CurrentWeather is used for stations without one in
+// Regions. The latest publish date of the 2 is returned.
+func mergeReadings(regions *Regions, currentWeather *CurrentWeather) (readings map[string]Region, pubDate time.Time) {
+	readings = make(map[string]Region, len(stations))
+	if regions != nil {
+		pubDate = regions.PubDate
+		for _, region := range regions.Regions {
+			readings[region.ShortName] = region.Trusted()
+		}
+	}
+	if currentWeather != nil {
+		if currentWeather.PubDate.After(pubDate) {
+			pubDate = currentWeather.PubDate
+		}
+		for id, reading := range currentWeather.Stations {
+			if len(reading.Quality) > 0 {
+				continue
+			}
+			region, ok := readings[id]
+			if !ok {
+				region = Region{Name: reading.Name, ShortName: id}
+			}
+			if region.CurrentTemp == nil {
+				region.CurrentTemp = NewTemperature(float64(reading.Temperature))
+			}
+			readings[id] = region
+		}
+	}
+	return
+}
+
+// Nearby finds the latest readings around a point from the Regions and
+// the CurrentWeather data, either of which can be nil. Temperature in
+// CurrentWeather is used for stations without one in Regions. Up to n
+// nearest stations with readings are listed (all if n <= 0). Stations
+// beyond NearbyRange are ignored, so a point far from Hong Kong has no
+// stations and no readings.
+func Nearby(lat, lon float64, regions *Regions, currentWeather *CurrentWeather, n int) *NearbyConditions {
+	nearby := &NearbyConditions{
+		Latitude:  lat,
+		Longitude: lon,
+		Sources:   make(map[string]NearbySource),
+	}
+
+	// merge the readings of the 2 reports by station
+	readings, pubDate := mergeReadings(regions, currentWeather)
+	nearby.PubDate = pubDate
+
+	// order the readings by distance of stations in range
+	// (readings of stations with unknown location are ignored)
+	list := make([]NearbyRegion, 0, len(readings))
+	for id, region := range readings {
+		station, ok := stations[id]
+		if !ok {
+			continue
+		}
+		if distance := station.DistanceTo(lat, lon); distance <= NearbyRange {
+			list = append(list, NearbyRegion{
+				Region:   region,
+				Distance: distance,
+			})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Distance < list[j].Distance
+	})
+
+	// take each reading from the nearest station having it
+	typ := reflect.TypeOf(Region{})
+	conditions := reflect.ValueOf(&nearby.Conditions).Elem()
+	for tag, field := range regionDataFields {
+		if tag == "region" || tag == "-" || tag == "" {
+			continue
+		}
+		for _, item := range list {
+			value := reflect.ValueOf(item.Region).Field(field.Index)
+			if value.IsZero() {
+				continue
+			}
+			conditions.Field(field.Index).Set(value)
+			nearby.Sources[typ.Field(field.Index).Name] = NearbySource{
+				ShortName: item.ShortName,
+				Name:      item.Name,
+				Distance:  item.Distance,
+			}
+			break
+		}
+	}
+
+	if nearby.Conditions.WindSpeed != nil {
+		force := nearby.Conditions.WindSpeed.Beaufort()
+		nearby.Conditions.WindForce = &force
+	}
+
+	if n > 0 && n < len(list) {
+		list = list[:n]
+	}
+	nearby.Stations = list
+	return nearby
+}
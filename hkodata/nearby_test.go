@@ -0,0 +1,126 @@
+package hkodata_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/yookoala/weatherhk/hkodata"
+)
+
+func TestDistance(t *testing.T) {
+	// Hong Kong Observatory to Chek Lap Kok
+	if have := hkodata.Distance(22.3019, 114.1742, 22.3094, 113.9219); have < 25.5 || have > 26.5 {
+		t.Errorf("expected about 26km, got %v", have)
+	}
+	if want, have := 0.0, hkodata.Distance(22.3019, 114.1742, 22.3019, 114.1742); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
+
+func TestNearestStations(t *testing.T) {
+	nearest := hkodata.NearestStations(22.3020, 114.1740, 3)
+	if want, have := 3, len(nearest); want != have {
+		t.Fatalf("expected %#v, got %#v", want, have)
+	}
+	if want, have := "hko", nearest[0].ID; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	for i := 1; i < len(nearest); i++ {
+		if nearest[i].Distance < nearest[i-1].Distance {
+			t.Errorf("stations not ordered by distance: %#v", nearest)
+		}
+	}
+}
+
+func TestNearby(t *testing.T) {
+	file, err := os.Open("./test/region_json.201612191037.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	defer file.Close()
+	regions, err := hkodata.DecodeRegionJSON(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	// next to the Observatory, which has no wind reading
+	nearby := hkodata.Nearby(22.3020, 114.1740, regions, nil, 3)
+	if want, have := regions.PubDate, nearby.PubDate; !want.Equal(have) {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+	if want, have := 3, len(nearby.Stations); want != have {
+		t.Fatalf("expected %#v, got %#v", want, have)
+	}
+	if want, have := "hko", nearby.Stations[0].ShortName; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := hkodata.NewTemperature(20.8), nearby.Conditions.CurrentTemp; *want != *have {
+		t.Errorf("expected %#v, got %#v", *want, *have)
+	}
+	if want, have := "hko", nearby.Sources["CurrentTemp"].ShortName; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	// wind reading falls back to the next nearest station
+	if nearby.Conditions.WindSpeed == nil {
+		t.Fatalf("expected wind speed, got nil")
+	}
+	source := nearby.Sources["WindSpeed"]
+	if source.ShortName == "hko" || source.Distance <= nearby.Sources["CurrentTemp"].Distance {
+		t.Errorf("unexpected source of wind speed: %#v", source)
+	}
+}
+
+func TestNearby_currentWeather(t *testing.T) {
+	file, err := os.Open("./test/CurrentWeather.201612172144.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	defer file.Close()
+	cw, err := hkodata.DecodeCurrentWeather(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	// near King's Park
+	nearby := hkodata.Nearby(22.3119, 114.1728, nil, cw, 0)
+	if want, have := cw.PubDate, nearby.PubDate; !want.Equal(have) {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+	if want, have := len(cw.Stations), len(nearby.Stations); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := "kp", nearby.Sources["CurrentTemp"].ShortName; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := cw.Stations["kp"].Temperature, *nearby.Conditions.CurrentTemp; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if nearby.Conditions.RelativeHumidity != nil {
+		t.Errorf("expected nil, got %#v", *nearby.Conditions.RelativeHumidity)
+	}
+}
+
+func TestNearby_outOfRange(t *testing.T) {
+	file, err := os.Open("./test/region_json.201612191037.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	defer file.Close()
+	regions, err := hkodata.DecodeRegionJSON(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	// London
+	nearby := hkodata.Nearby(51.5074, -0.1278, regions, nil, 0)
+	if want, have := 0, len(nearby.Stations); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := 0, len(nearby.Sources); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if nearby.Conditions.CurrentTemp != nil {
+		t.Errorf("expected nil, got %#v", *nearby.Conditions.CurrentTemp)
+	}
+}
@@ -0,0 +1,106 @@
+package hkodata
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// DayForecast contains weather forecast of a single day
+type DayForecast struct {
+	Date                time.Time
+	WeekDay             time.Weekday
+	Wind                string
+	Weather             string
+	MaxTemp             Temperature
+	MinTemp             Temperature
+	MaxRelativeHumidity RelativeHumidity
+	MinRelativeHumidity RelativeHumidity
+	Icon                int
+	IconDesc            string
+}
+
+// NineDayForecast contains the 9-day weather forecast
+type NineDayForecast struct {
+	PubDate          time.Time
+	GeneralSituation string
+	Forecasts        []DayForecast
+}
+
+// Expires implements Expirer interface
+// (the bulletin is only updated a few times a day, but there is
+// no fixed schedule to rely on)
+func (forecast NineDayForecast) Expires() time.Time {
+	return forecast.PubDate.Add(60 * time.Minute)
+}
+
+var reIconCode = regexp.MustCompile(`(\d+)`)
+
+// parseIconCode parses HKO weather icon code from icon file name
+// (e.g. "pic52.png")
+func parseIconCode(str string) (code int, err error) {
+	submatches := reIconCode.FindStringSubmatch(str)
+	if submatches == nil {
+		err = fmt.Errorf("no icon code in %#v", str)
+		return
+	}
+	return strconv.Atoi(submatches[1])
+}
+
+// parseFloat parses a float number of a named field and append
+// warning, if any, to the given ParseError
+func parseFloat(field, str string, parseErrors *ParseError) float64 {
+	val, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		parseErrors.warn(field, str, "unable to parse number")
+	}
+	return val
+}
+
+// NineDayForecast parses the F9D section into NineDayForecast
+func (one OneJSON) NineDayForecast(opts ...DecodeOption) (forecast *NineDayForecast, err error) {
+	f9d := one.F9D
+	parseErrors := make(ParseError, 0, 20)
+
+	forecast = &NineDayForecast{
+		GeneralSituation: f9d.GeneralSituation,
+		Forecasts:        make([]DayForecast, 0, len(f9d.WeatherForecast)),
+	}
+	forecast.PubDate, err = time.ParseInLocation("200601021504", f9d.BulletinDate+f9d.BulletinTime, HKT)
+	if err != nil {
+		parseErrors.fatal("F9D.BulletinTime", f9d.BulletinDate+f9d.BulletinTime, "unable to parse bulletin time")
+	}
+
+	for i, day := range f9d.WeatherForecast {
+		field := fmt.Sprintf("F9D.WeatherForecast[%d]", i)
+		dayForecast := DayForecast{
+			Wind:                day.ForecastWind,
+			Weather:             day.ForecastWeather,
+			MaxTemp:             Temperature(parseFloat(field+".ForecastMaxtemp", day.ForecastMaxtemp, &parseErrors)),
+			MinTemp:             Temperature(parseFloat(field+".ForecastMintemp", day.ForecastMintemp, &parseErrors)),
+			MaxRelativeHumidity: RelativeHumidity(parseFloat(field+".ForecastMaxrh", day.ForecastMaxrh, &parseErrors) / 100),
+			MinRelativeHumidity: RelativeHumidity(parseFloat(field+".ForecastMinrh", day.ForecastMinrh, &parseErrors) / 100),
+			IconDesc:            day.IconDesc,
+		}
+
+		if dayForecast.Date, err = time.ParseInLocation("20060102", day.ForecastDate, HKT); err != nil {
+			parseErrors.warn(field+".ForecastDate", day.ForecastDate, "unable to parse date")
+		}
+		dayForecast.WeekDay = dayForecast.Date.Weekday()
+		if weekDay, err := strconv.Atoi(day.WeekDay); err != nil || weekDay != int(dayForecast.WeekDay) {
+			parseErrors.warn(field+".WeekDay", day.WeekDay, "does not match ForecastDate %#v", day.ForecastDate)
+		}
+		if dayForecast.Icon, err = parseIconCode(day.ForecastIcon); err != nil {
+			parseErrors.warn(field+".ForecastIcon", day.ForecastIcon, "unable to parse icon code")
+		}
+
+		forecast.Forecasts = append(forecast.Forecasts, dayForecast)
+	}
+
+	err = parseErrors.result(newDecodeOptions(opts))
+	if parseErrors.Fatal() {
+		forecast = nil
+	}
+	return
+}
@@ -0,0 +1,106 @@
+package hkodata_test
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	pretty "github.com/tonnerre/golang-pretty"
+	"github.com/yookoala/weatherhk/hkodata"
+)
+
+func TestOneJSON_NineDayForecast(t *testing.T) {
+	file, err := os.Open("./test/one_json_uc.201612191917.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	defer file.Close()
+
+	one, err := hkodata.DecodeOneJSON(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	forecast, err := one.NineDayForecast()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if want, have := time.Date(2016, time.December, 19, 16, 30, 0, 0, hkodata.HKT), forecast.PubDate; !want.Equal(have) {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+	if want, have := hkodata.HKT.String(), forecast.PubDate.Location().String(); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := 9, len(forecast.Forecasts); want != have {
+		t.Fatalf("expected %#v, got %#v", want, have)
+	}
+
+	expected := []hkodata.DayForecast{
+		hkodata.DayForecast{
+			Date:                time.Date(2016, time.December, 20, 0, 0, 0, 0, hkodata.HKT),
+			WeekDay:             time.Tuesday,
+			Wind:                "東風4級。",
+			Weather:             "漸轉多雲。日間短暫時間有陽光。晚上有一兩陣雨。",
+			MaxTemp:             hkodata.Temperature(23),
+			MinTemp:             hkodata.Temperature(19),
+			MaxRelativeHumidity: hkodata.RelativeHumidity(.90),
+			MinRelativeHumidity: hkodata.RelativeHumidity(.70),
+			Icon:                52,
+			IconDesc:            "短暫陽光",
+		},
+		hkodata.DayForecast{
+			Date:                time.Date(2016, time.December, 25, 0, 0, 0, 0, hkodata.HKT),
+			WeekDay:             time.Sunday,
+			Wind:                "東風4至5級，初時離岸間中6級。",
+			Weather:             "初時有一兩陣微雨。日間短暫時間有陽光。",
+			MaxTemp:             hkodata.Temperature(22),
+			MinTemp:             hkodata.Temperature(19),
+			MaxRelativeHumidity: hkodata.RelativeHumidity(.85),
+			MinRelativeHumidity: hkodata.RelativeHumidity(.70),
+			Icon:                52,
+			IconDesc:            "短暫陽光",
+		},
+	}
+
+	for i, j := range []int{0, 5} {
+		if want, have := expected[i], forecast.Forecasts[j]; !reflect.DeepEqual(want, have) {
+			t.Errorf("unexpected difference in forecast[%d] (want != have)", j)
+			for _, desc := range pretty.Diff(want, have) {
+				t.Log("\tforecast." + desc)
+			}
+		}
+	}
+}
+
+func TestOneJSON_NineDayForecast_parseError(t *testing.T) {
+	one := hkodata.OneJSON{
+		F9D: hkodata.OneJSONF9D{
+			BulletinDate: "20161219",
+			BulletinTime: "1630",
+			WeatherForecast: []hkodata.OneJSONF9DDay{
+				{
+					ForecastDate:    "20161220",
+					ForecastMaxtemp: "23",
+					ForecastMintemp: "N/A",
+					ForecastMaxrh:   "90",
+					ForecastMinrh:   "70",
+					ForecastIcon:    "pic52.png",
+					WeekDay:         "2",
+				},
+			},
+		},
+	}
+
+	forecast, err := one.NineDayForecast()
+	if forecast == nil {
+		t.Fatalf("expected forecast, got nil")
+	}
+	if _, ok := err.(hkodata.ParseError); !ok {
+		t.Errorf("expected hkodata.ParseError, got %#v", err)
+	}
+	if want, have := hkodata.Temperature(23), forecast.Forecasts[0].MaxTemp; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
@@ -0,0 +1,186 @@
+package hkodata
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"time"
+)
+
+// OneJSONFLW represents the local weather forecast section (FLW) of
+// `one_json_uc.xml`
+type OneJSONFLW struct {
+	BulletinDate      string
+	BulletinTime      string
+	GeneralSituation  string
+	TCInfo            string
+	FireDangerWarning string
+	ForecastPeriod    string
+	ForecastDesc      string
+	OutlookTitle      string
+	OutlookContent    string
+	Icon1             string
+	Icon2             string
+}
+
+// OneJSONF9DDay represents a single day in the 9-day weather forecast
+// section (F9D) of `one_json_uc.xml`
+type OneJSONF9DDay struct {
+	ForecastDate    string
+	ForecastWind    string
+	ForecastWeather string
+	ForecastMaxtemp string
+	ForecastMintemp string
+	ForecastMaxrh   string
+	ForecastMinrh   string
+	ForecastIcon    string
+	IconDesc        string
+	WeekDay         string
+}
+
+// OneJSONF9D represents the 9-day weather forecast section (F9D) of
+// `one_json_uc.xml`
+type OneJSONF9D struct {
+	BulletinDate     string
+	BulletinTime     string
+	NPTemp           string
+	GeneralSituation string
+	WeatherForecast  []OneJSONF9DDay
+}
+
+// OneJSONRHRREAD represents the regional hourly reading section (RHRREAD)
+// of `one_json_uc.xml`
+type OneJSONRHRREAD struct {
+	BulletinDate     string
+	BulletinTime     string
+	UVIndex          string
+	Intensity        string
+	HKOTemp          string `json:"hkotemp"`
+	HKORH            string `json:"hkorh"`
+	FormattedObsTime string
+}
+
+// OneJSONFUV represents the UV index forecast section (FUV) of
+// `one_json_uc.xml`
+type OneJSONFUV struct {
+	BulletinDate                  string
+	BulletinTime                  string
+	ForecastTimeInfoMaxUV         string
+	ForecastTimeInfoMaxUvCategory string
+	ForecastTimeInfoDate          string
+	Message                       string
+}
+
+// OneJSONTide represents a tide entry in the CMN section of
+// `one_json_uc.xml`
+type OneJSONTide struct {
+	Type   string `json:"type"`
+	Time   string `json:"time"`
+	Height string `json:"height"`
+}
+
+// OneJSONCMN represents the calendar and astronomical section (CMN) of
+// `one_json_uc.xml`
+type OneJSONCMN struct {
+	SolarTerm     string
+	PublicHoliday string
+	GregorianDate string
+	LunarDate     string
+	SunriseTime   string        `json:"sunriseTime"`
+	SunsetTime    string        `json:"sunsetTime"`
+	MoonriseTime  string        `json:"moonriseTime"`
+	MoonsetTime   string        `json:"moonsetTime"`
+	ForecastDate  string        `json:"forecastDate"`
+	Tide          []OneJSONTide `json:"tide"`
+}
+
+// OneJSONSWT represents the special weather tips section (SWT) of
+// `one_json_uc.xml`
+type OneJSONSWT struct {
+	HeadLine1        string `json:"headLine1"`
+	HeadLine2        string `json:"headLine2"`
+	HeadLine3        string `json:"headLine3"`
+	HeadLine4        string `json:"headLine4"`
+	HeadLine5        string `json:"headLine5"`
+	SMSSWT           string `json:"smsSwt"`
+	TornadoReport    string `json:"tornadoReport"`
+	WaterspoutReport string `json:"waterspoutReport"`
+	GustForecast     string `json:"gustForecast"`
+	HotAdvisory      string `json:"hotAdvisory"`
+}
+
+// OneJSONCurrWx represents the current weather section (currwx) of
+// `one_json_uc.xml`
+type OneJSONCurrWx struct {
+	BulletinTime string `json:"btime"`
+	Temp         string `json:"temp"`
+	RH           string `json:"rh"`
+}
+
+// OneJSONLightning represents a time slot of the lightning map legend
+// in the lightning_info section of `one_json_uc.xml`
+type OneJSONLightning struct {
+	Date  string `json:"date"`
+	Time  string `json:"time"`
+	Color string `json:"color"`
+}
+
+// OneJSONHeader represents the page header section of `one_json_uc.xml`
+type OneJSONHeader struct {
+	FestivalCode      string `json:"festival_code"`
+	SolarTermCode     string `json:"solar_term_code"`
+	DateTimeDisplayZh string `json:"dateTimeDisplay_uc"`
+	DateTimeDisplayEn string `json:"dateTimeDisplay_en"`
+	LunarDateZh       string `json:"lunar_date_uc"`
+	SolarTermZh       string `json:"solar_term_uc"`
+	SolarTermEn       string `json:"solar_term_en"`
+	PublicHolidayZh   string `json:"publicholiday_uc"`
+	PublicHolidayEn   string `json:"publicholiday_en"`
+}
+
+// OneJSON represents data from HKO non-public API endpoint
+// `one_json_uc.xml` (the bundle used by HKO homepage)
+type OneJSON struct {
+	PubDate       time.Time
+	FLW           OneJSONFLW
+	F9D           OneJSONF9D
+	RHRREAD       OneJSONRHRREAD
+	FUV           OneJSONFUV
+	CMN           OneJSONCMN
+	SWT           OneJSONSWT
+	CurrWx        OneJSONCurrWx      `json:"currwx"`
+	LightningInfo []OneJSONLightning `json:"lightning_info"`
+	Header        OneJSONHeader      `json:"header"`
+}
+
+// Expires implements Expirer interface
+func (one OneJSON) Expires() time.Time {
+	return one.PubDate.Add(10 * time.Minute)
+}
+
+// DecodeOneJSON decodes non-public API endpoint `one_json_uc.xml` of
+// HKO website
+func DecodeOneJSON(r io.Reader, opts ...DecodeOption) (one *OneJSON, err error) {
+	parseErrors := make(ParseError, 0, 1)
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		parseErrors.fatal("body", "", "unable to read: %s", err)
+		return nil, parseErrors
+	}
+	one = &OneJSON{}
+	if err = json.Unmarshal(body, one); err != nil {
+		parseErrors.fatal("body", rawExcerpt(body), "unable to parse JSON: %s", err)
+		return nil, parseErrors
+	}
+
+	// use the current weather bulletin time as the publish date
+	if one.PubDate, err = time.Parse("200601021504-0700", one.CurrWx.BulletinTime+"+0800"); err != nil {
+		parseErrors.warn("currwx.btime", one.CurrWx.BulletinTime, "unable to parse bulletin time")
+	}
+
+	err = parseErrors.result(newDecodeOptions(opts))
+	if parseErrors.Fatal() {
+		one = nil
+	}
+	return
+}
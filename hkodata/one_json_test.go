@@ -0,0 +1,121 @@
+package hkodata_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yookoala/weatherhk/hkodata"
+)
+
+func TestDecodeOneJSON(t *testing.T) {
+	file, err := os.Open("./test/one_json_uc.201612191917.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	defer file.Close()
+
+	one, err := hkodata.DecodeOneJSON(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if want, have := time.Date(2016, time.December, 19, 19, 0, 0, 0, hkodata.HKT), one.PubDate; !want.Equal(have) {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+	if want, have := time.Date(2016, time.December, 19, 19, 10, 0, 0, hkodata.HKT), one.Expires(); !want.Equal(have) {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+
+	// FLW
+	if want, have := "1845", one.FLW.BulletinTime; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := "", one.FLW.TCInfo; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := "76", one.FLW.Icon1; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	// F9D
+	if want, have := 9, len(one.F9D.WeatherForecast); want != have {
+		t.Fatalf("expected %#v, got %#v", want, have)
+	}
+	if want, have := "20161220", one.F9D.WeatherForecast[0].ForecastDate; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := "pic92.png", one.F9D.WeatherForecast[8].ForecastIcon; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	// RHRREAD
+	if want, have := "//", one.RHRREAD.UVIndex; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := "67", one.RHRREAD.HKORH; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	// FUV
+	if want, have := "5", one.FUV.ForecastTimeInfoMaxUV; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	// CMN
+	if want, have := "06:57", one.CMN.SunriseTime; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := 4, len(one.CMN.Tide); want != have {
+		t.Fatalf("expected %#v, got %#v", want, have)
+	}
+	if want, have := "0.6", one.CMN.Tide[1].Height; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	// SWT
+	if want, have := "", one.SWT.HotAdvisory; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	// currwx
+	if want, have := "21.0", one.CurrWx.Temp; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	// lightning_info
+	if want, have := 6, len(one.LightningInfo); want != have {
+		t.Fatalf("expected %#v, got %#v", want, have)
+	}
+	if want, have := "18:40-18:44", one.LightningInfo[0].Time; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	// header
+	if want, have := "19 Dec 2016 (Mon)", one.Header.DateTimeDisplayEn; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
+
+func TestDecodeOneJSON_invalid(t *testing.T) {
+	one, err := hkodata.DecodeOneJSON(strings.NewReader("some non-sense"))
+	if err == nil {
+		t.Errorf("expected error, got nil")
+	}
+	if one != nil {
+		t.Errorf("expected nil, got %#v", one)
+	}
+	parseErrors, ok := err.(hkodata.ParseError)
+	if !ok {
+		t.Fatalf("expected hkodata.ParseError, got %#v", err)
+	}
+	if want, have := true, parseErrors.Fatal(); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if fieldErr, ok := parseErrors[0].(*hkodata.FieldError); !ok {
+		t.Errorf("expected *hkodata.FieldError, got %#v", parseErrors[0])
+	} else if want, have := "some non-sense", fieldErr.Raw; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
@@ -0,0 +1,260 @@
+package hkodata
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// QualityFlag marks a reading as suspect for a reason found in quality
+// control
+type QualityFlag int
+
+const (
+	// QualityOutOfRange marks a reading out of the climatological range
+	// of Hong Kong
+	QualityOutOfRange QualityFlag = iota
+
+	// QualityPlaceholder marks a zero reading that is likely a
+	// placeholder of missing data
+	QualityPlaceholder
+
+	// QualityInconsistent marks a reading inconsistent with other
+	// readings of the same station (e.g. maximum below minimum)
+	QualityInconsistent
+
+	// QualitySpike marks a reading that changed abruptly since the
+	// previous report
+	QualitySpike
+)
+
+// String implements fmt.Stringer
+func (flag QualityFlag) String() string {
+	switch flag {
+	case QualityOutOfRange:
+		return "out-of-range"
+	case QualityPlaceholder:
+		return "placeholder"
+	case QualityInconsistent:
+		return "inconsistent"
+	case QualitySpike:
+		return "spike"
+	}
+	return "unknown"
+}
+
+// MarshalJSON implements json.Marshaler
+func (flag QualityFlag) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(flag.String())), nil
+}
+
+// Quality contains the quality flags of readings, keyed by field name
+type Quality map[string][]QualityFlag
+
+// flag adds a flag to the field, if not already flagged so
+func (quality Quality) flag(field string, flag QualityFlag) {
+	for _, existing := range quality[field] {
+		if existing == flag {
+			return
+		}
+	}
+	quality[field] = append(quality[field], flag)
+}
+
+// qualityRange is the range of plausible values of a reading
+type qualityRange struct {
+	min, max float64
+}
+
+func (r qualityRange) contains(val float64) bool {
+	return val >= r.min && val <= r.max
+}
+
+// climatological ranges of readings in Hong Kong, with some margin
+// over the records (e.g. -6.0 degree celcius at Tai Mo Shan in 2016)
+var (
+	temperatureRange      = qualityRange{-8, 42}
+	relativeHumidityRange = qualityRange{0, 1}
+	windSpeedRange        = qualityRange{0, 250}
+)
+
+// regionQualityFields are the numeric readings of Region checked in
+// quality control, in order
+var regionQualityFields = []struct {
+	field string
+	valid qualityRange
+}{
+	{"CurrentTemp", temperatureRange},
+	{"RelativeHumidity", relativeHumidityRange},
+	{"WindSpeed", windSpeedRange},
+	{"MaxTemp", temperatureRange},
+	{"MinTemp", temperatureRange},
+}
+
+// maximum changes of readings between reports not considered as spikes
+var regionSpikeLimits = map[string]float64{
+	"CurrentTemp":      4,
+	"RelativeHumidity": 0.3,
+	"WindSpeed":        50,
+}
+
+// maxSpikeInterval is the longest time between reports to compare for
+// spikes. Reports further apart could have changed legitimately.
+const maxSpikeInterval = 30 * time.Minute
+
+// readingOf returns the value of a numeric reading of the region
+func (region Region) readingOf(field string) (val float64, ok bool) {
+	value := reflect.ValueOf(region).FieldByName(field)
+	if !value.IsValid() || value.Kind() != reflect.Ptr || value.IsNil() {
+		return 0, false
+	}
+	return value.Elem().Float(), true
+}
+
+// Suspect reports if the reading in the field is flagged in quality
+// control
+func (region Region) Suspect(field string) bool {
+	return len(region.Quality[field]) > 0
+}
+
+// Trusted returns a copy of the region with suspect readings removed
+func (region Region) Trusted() Region {
+	if len(region.Quality) == 0 {
+		return region
+	}
+	value := reflect.ValueOf(&region).Elem()
+	for field := range region.Quality {
+		if f := value.FieldByName(field); f.IsValid() && f.Kind() == reflect.Ptr {
+			f.Set(reflect.Zero(f.Type()))
+		}
+	}
+	if region.WindSpeed == nil {
+		region.WindForce = nil
+	}
+	return region
+}
+
+// CheckQuality flags suspect readings of the region: values out of
+// climatological range, zeros as placeholders of missing data (i.e. zero
+// relative humidity, or more than 1 reading being zero) and maximum
+// temperature below minimum
+func (region *Region) CheckQuality() {
+	quality := make(Quality)
+
+	zeros := 0
+	for _, check := range regionQualityFields {
+		if val, ok := region.readingOf(check.field); ok && val == 0 {
+			zeros++
+		}
+	}
+	for _, check := range regionQualityFields {
+		val, ok := region.readingOf(check.field)
+		if !ok {
+			continue
+		}
+		if val == 0 && (zeros > 1 || check.field == "RelativeHumidity") {
+			quality.flag(check.field, QualityPlaceholder)
+		} else if !check.valid.contains(val) {
+			quality.flag(check.field, QualityOutOfRange)
+		}
+	}
+	if region.MaxTemp != nil && region.MinTemp != nil && *region.MaxTemp < *region.MinTemp {
+		quality.flag("MaxTemp", QualityInconsistent)
+		quality.flag("MinTemp", QualityInconsistent)
+	}
+
+	region.Quality = nil
+	if len(quality) > 0 {
+		region.Quality = quality
+	}
+}
+
+// CheckSpikes flags readings that changed abruptly since the previous
+// report of the same stations. Readings suspect in either report are not
+// compared. Nothing is flagged if the previous report is nil, not
+// earlier or too long ago.
+func (regions *Regions) CheckSpikes(previous *Regions) {
+	if previous == nil || !previous.PubDate.Before(regions.PubDate) ||
+		regions.PubDate.Sub(previous.PubDate) > maxSpikeInterval {
+		return
+	}
+
+	before := make(map[string]Region, len(previous.Regions))
+	for _, region := range previous.Regions {
+		before[region.ShortName] = region
+	}
+	for i := range regions.Regions {
+		region := &regions.Regions[i]
+		last, ok := before[region.ShortName]
+		if !ok {
+			continue
+		}
+		for field, limit := range regionSpikeLimits {
+			if region.Suspect(field) || last.Suspect(field) {
+				continue
+			}
+			val, ok1 := region.readingOf(field)
+			lastVal, ok2 := last.readingOf(field)
+			if ok1 && ok2 && math.Abs(val-lastVal) > limit {
+				if region.Quality == nil {
+					region.Quality = make(Quality)
+				}
+				region.Quality.flag(field, QualitySpike)
+			}
+		}
+	}
+}
+
+// Clone returns a deep copy of the regions data, which is not affected
+// by later changes (e.g. by ConvertUnits) to the original
+func (regions Regions) Clone() *Regions {
+	clone := &Regions{
+		PubDate: regions.PubDate,
+		Regions: make([]Region, len(regions.Regions)),
+	}
+	for i, region := range regions.Regions {
+		value := reflect.ValueOf(&region).Elem()
+		for j, numField := 0, value.NumField(); j < numField; j++ {
+			field := value.Field(j)
+			if field.Kind() == reflect.Ptr && !field.IsNil() {
+				copied := reflect.New(field.Type().Elem())
+				copied.Elem().Set(field.Elem())
+				field.Set(copied)
+			}
+		}
+		if region.Quality != nil {
+			quality := make(Quality, len(region.Quality))
+			for field, flags := range region.Quality {
+				quality[field] = append([]QualityFlag(nil), flags...)
+			}
+			region.Quality = quality
+		}
+		clone.Regions[i] = region
+	}
+	return clone
+}
+
+// checkQuality flags suspect readings in the CurrentWeather report: values
+// out of climatological range and zero relative humidity
+func (currentWeather *CurrentWeather) checkQuality() {
+	quality := make(Quality)
+	if !temperatureRange.contains(float64(currentWeather.AirTemperature)) {
+		quality.flag("AirTemperature", QualityOutOfRange)
+	}
+	if currentWeather.RelativeHumidity == 0 {
+		quality.flag("RelativeHumidity", QualityPlaceholder)
+	} else if !relativeHumidityRange.contains(float64(currentWeather.RelativeHumidity)) {
+		quality.flag("RelativeHumidity", QualityOutOfRange)
+	}
+	if len(quality) > 0 {
+		currentWeather.Quality = quality
+	}
+
+	for id, station := range currentWeather.Stations {
+		if !temperatureRange.contains(float64(station.Temperature)) {
+			station.Quality = []QualityFlag{QualityOutOfRange}
+			currentWeather.Stations[id] = station
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package hkodata_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yookoala/weatherhk/hkodata"
+)
+
+func TestRegion_CheckQuality(t *testing.T) {
+	region := hkodata.Region{
+		ShortName:        "hka",
+		CurrentTemp:      hkodata.NewTemperature(45.2),
+		RelativeHumidity: hkodata.NewRelativeHumidity(0),
+		WindSpeed:        hkodata.NewSpeed(0),
+		MaxTemp:          hkodata.NewTemperature(18.2),
+		MinTemp:          hkodata.NewTemperature(19.5),
+	}
+	region.CheckQuality()
+
+	expected := hkodata.Quality{
+		"CurrentTemp":      {hkodata.QualityOutOfRange},
+		"RelativeHumidity": {hkodata.QualityPlaceholder},
+		"WindSpeed":        {hkodata.QualityPlaceholder},
+		"MaxTemp":          {hkodata.QualityInconsistent},
+		"MinTemp":          {hkodata.QualityInconsistent},
+	}
+	if want, have := expected, region.Quality; !reflect.DeepEqual(want, have) {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	b, err := json.Marshal(region.Quality)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want, have := `{"CurrentTemp":["out-of-range"],"MaxTemp":["inconsistent"],"MinTemp":["inconsistent"],"RelativeHumidity":["placeholder"],"WindSpeed":["placeholder"]}`, string(b); want != have {
+		t.Errorf("\nexpected %s\ngot      %s", want, have)
+	}
+
+	// suspect readings are removed from trusted copy only
+	trusted := region.Trusted()
+	if trusted.CurrentTemp != nil || trusted.RelativeHumidity != nil || trusted.MaxTemp != nil {
+		t.Errorf("expected suspect readings removed, got %#v", trusted)
+	}
+	if region.CurrentTemp == nil {
+		t.Errorf("expected original readings kept")
+	}
+}
+
+func TestRegion_CheckQuality_calm(t *testing.T) {
+	region := hkodata.Region{
+		ShortName:        "hka",
+		CurrentTemp:      hkodata.NewTemperature(21.2),
+		RelativeHumidity: hkodata.NewRelativeHumidity(.81),
+		WindSpeed:        hkodata.NewSpeed(0),
+	}
+	region.CheckQuality()
+	if region.Quality != nil {
+		t.Errorf("expected no flag for calm wind, got %#v", region.Quality)
+	}
+}
+
+func TestRegions_CheckSpikes(t *testing.T) {
+	pubDate := time.Date(2016, time.December, 19, 10, 20, 0, 0, hkodata.HKT)
+	previous := &hkodata.Regions{
+		PubDate: pubDate.Add(-10 * time.Minute),
+		Regions: []hkodata.Region{
+			{
+				ShortName:        "hka",
+				CurrentTemp:      hkodata.NewTemperature(24.1),
+				RelativeHumidity: hkodata.NewRelativeHumidity(.54),
+			},
+			{
+				ShortName:   "cch",
+				CurrentTemp: hkodata.NewTemperature(22.0),
+			},
+		},
+	}
+	regions := &hkodata.Regions{
+		PubDate: pubDate,
+		Regions: []hkodata.Region{
+			{
+				ShortName:        "hka",
+				CurrentTemp:      hkodata.NewTemperature(31.6),
+				RelativeHumidity: hkodata.NewRelativeHumidity(.56),
+			},
+			{
+				ShortName:   "cch",
+				CurrentTemp: hkodata.NewTemperature(21.4),
+			},
+		},
+	}
+
+	// reports too far apart are not compared
+	stale := previous.Clone()
+	stale.PubDate = pubDate.Add(-2 * time.Hour)
+	regions.CheckSpikes(stale)
+	if regions.Regions[0].Quality != nil {
+		t.Errorf("expected no flag, got %#v", regions.Regions[0].Quality)
+	}
+
+	regions.CheckSpikes(previous)
+	expected := hkodata.Quality{
+		"CurrentTemp": {hkodata.QualitySpike},
+	}
+	if want, have := expected, regions.Regions[0].Quality; !reflect.DeepEqual(want, have) {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if regions.Regions[1].Quality != nil {
+		t.Errorf("expected no flag, got %#v", regions.Regions[1].Quality)
+	}
+}
+
+func TestRegions_Clone(t *testing.T) {
+	regions := &hkodata.Regions{
+		Regions: []hkodata.Region{
+			{
+				ShortName:   "hka",
+				CurrentTemp: hkodata.NewTemperature(24.1),
+			},
+		},
+	}
+	clone := regions.Clone()
+	hkodata.ConvertUnits(regions, hkodata.UnitsImperial)
+	if want, have := hkodata.Temperature(24.1), *clone.Regions[0].CurrentTemp; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
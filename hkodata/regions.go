@@ -1,7 +1,10 @@
 package hkodata
 
 import (
+	"bytes"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
 	"reflect"
 	"strconv"
@@ -32,7 +35,7 @@ func init() {
 		},
 		"cen": {
 			Zh: "中環",
-			En: "",
+			En: "Central Pier",
 		},
 		"gi": {
 			Zh: "青洲",
@@ -242,10 +245,13 @@ type Region struct {
 	ShortName        string            `hkodata:"region"`
 	CurrentTemp      *Temperature      `hkodata:"temp" json:"CurrentTemp,omitempty"`
 	RelativeHumidity *RelativeHumidity `hkodata:"rh" json:"RelativeHumidity,omitempty"`
-	WindDirection    string            `hkodata:"wind" json:"WindDirection,omitempty"`
+	WindDirection    *WindDirection    `hkodata:"wind" json:"WindDirection,omitempty"`
 	WindSpeed        *Speed            `hkodata:"speed" json:"WindSpeed,omitempty"`
+	WindForce        *Beaufort         `hkodata:"-" json:"WindForce,omitempty"` // by WindSpeed
 	MaxTemp          *Temperature      `hkodata:"maxtemp" json:"MaxTemp,omitempty"`
 	MinTemp          *Temperature      `hkodata:"mintemp" json:"MinTemp,omitempty"`
+	Comfort          *ComfortIndices   `hkodata:"-" json:"Comfort,omitempty"` // see ComputeComfort
+	Quality          Quality           `hkodata:"-" json:"Quality,omitempty"` // see CheckQuality
 }
 
 // Regions represents data from HKO non-public API endpoint `region_json.xml`
@@ -262,23 +268,53 @@ func (regions Regions) Expires() time.Time {
 
 // DecodeRegionJSON decodes non-public API endpoint `region_json.xml` of
 // HKO website (2015 API)
-func DecodeRegionJSON(r io.Reader) (regions *Regions, err error) {
+func DecodeRegionJSON(r io.Reader, opts ...DecodeOption) (regions *Regions, err error) {
 	regions = &Regions{}
 	regions.Regions = make([]Region, 0, 20)
 
-	json := lzjson.Decode(r)
-	regions.PubDate, _ = time.Parse("200601021504-0700", json.Get("btime").String()+"+0800")
+	// for better error reporting
+	parseErrors := make(ParseError, 0, 10)
+
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		parseErrors.fatal("body", "", "unable to read: %s", err)
+		return nil, parseErrors
+	}
+	json := lzjson.Decode(bytes.NewReader(body))
+	if err = json.ParseError(); err != nil {
+		parseErrors.fatal("body", rawExcerpt(body), "unable to parse JSON: %s", err)
+		return nil, parseErrors
+	}
+	if json.Type() != lzjson.TypeObject {
+		// lzjson parses lazily, so syntax errors are not reported upfront
+		parseErrors.fatal("body", rawExcerpt(body), "expected JSON object")
+		return nil, parseErrors
+	}
+	btime := json.Get("btime").String()
+	if regions.PubDate, err = time.Parse("200601021504-0700", btime+"+0800"); err != nil {
+		parseErrors.fatal("btime", btime, "unable to parse bulletin time")
+	}
 
 	// parse the field names from JSON
 	var fieldNames []string
-	json.Get("fields").Unmarshal(&fieldNames)
+	if err = json.Get("fields").Unmarshal(&fieldNames); err != nil {
+		parseErrors.fatal("fields", string(json.Get("fields").Raw()), "unable to parse field names: %s", err)
+	}
 
 	// read data of all regions
 	jsonData := json.Get("datas")
 	for i, jsonDataLen := 0, jsonData.Len(); i < jsonDataLen; i++ {
 		var region Region
 		var fields []string
-		jsonData.GetN(i).Unmarshal(&fields)
+		if err = jsonData.GetN(i).Unmarshal(&fields); err != nil {
+			parseErrors.warn(fmt.Sprintf("datas[%d]", i), string(jsonData.GetN(i).Raw()), "unable to parse region data: %s", err)
+			continue
+		}
+		if len(fields) > len(fieldNames) {
+			parseErrors.warn(fmt.Sprintf("datas[%d]", i), string(jsonData.GetN(i).Raw()),
+				"expected at most %d fields, got %d", len(fieldNames), len(fields))
+			fields = fields[:len(fieldNames)]
+		}
 		regionVal := reflect.ValueOf(&region).Elem()
 
 		// 1. loop each data fields of a region
@@ -290,15 +326,27 @@ func DecodeRegionJSON(r io.Reader) (regions *Regions, err error) {
 			if structFieldDef, ok := regionDataFields[fieldName]; ok {
 				if fields[j] != "" {
 					switch structFieldDef.Type {
-					case "*Temperature":
-						val, _ := strconv.ParseFloat(fields[j], 64)
-						regionVal.Field(structFieldDef.Index).Set(reflect.ValueOf(NewTemperature(val)))
-					case "*RelativeHumidity":
-						val, _ := strconv.ParseFloat(fields[j], 64)
-						regionVal.Field(structFieldDef.Index).Set(reflect.ValueOf(NewRelativeHumidity(val / 100)))
-					case "*Speed":
-						val, _ := strconv.ParseFloat(fields[j], 64)
-						regionVal.Field(structFieldDef.Index).Set(reflect.ValueOf(NewSpeed(val)))
+					case "*Temperature", "*RelativeHumidity", "*Speed":
+						val, err := strconv.ParseFloat(fields[j], 64)
+						if err != nil {
+							parseErrors.warn(fmt.Sprintf("datas[%d].%s", i, fieldName), fields[j], "unable to parse number")
+							continue
+						}
+						switch structFieldDef.Type {
+						case "*Temperature":
+							regionVal.Field(structFieldDef.Index).Set(reflect.ValueOf(NewTemperature(val)))
+						case "*RelativeHumidity":
+							regionVal.Field(structFieldDef.Index).Set(reflect.ValueOf(NewRelativeHumidity(val / 100)))
+						case "*Speed":
+							regionVal.Field(structFieldDef.Index).Set(reflect.ValueOf(NewSpeed(val)))
+						}
+					case "*WindDirection":
+						dir, err := ParseWindDirection(fields[j])
+						if err != nil {
+							parseErrors.warn(fmt.Sprintf("datas[%d].%s", i, fieldName), fields[j], "unidentified wind direction")
+							continue
+						}
+						regionVal.Field(structFieldDef.Index).Set(reflect.ValueOf(NewWindDirection(dir)))
 					case "string":
 						regionVal.Field(structFieldDef.Index).Set(reflect.ValueOf(fields[j]))
 					default:
@@ -312,8 +360,22 @@ func DecodeRegionJSON(r io.Reader) (regions *Regions, err error) {
 		// parse the short name into longer verbose region name
 		region.Name, _ = regionNames[region.ShortName]
 
+		// flag suspect readings
+		region.CheckQuality()
+
+		// classify the wind speed
+		if region.WindSpeed != nil && !region.Suspect("WindSpeed") {
+			force := region.WindSpeed.Beaufort()
+			region.WindForce = &force
+		}
+
 		// append the result list
 		regions.Regions = append(regions.Regions, region)
 	}
+
+	err = parseErrors.result(newDecodeOptions(opts))
+	if parseErrors.Fatal() {
+		regions = nil
+	}
 	return
 }
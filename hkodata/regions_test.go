@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -29,8 +30,9 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "hka",
 			CurrentTemp:      hkodata.NewTemperature(24.1),
 			RelativeHumidity: hkodata.NewRelativeHumidity(.54),
-			WindDirection:    "E",
+			WindDirection:    hkodata.NewWindDirection(hkodata.WindEast),
 			WindSpeed:        hkodata.NewSpeed(24),
+			WindForce:        hkodata.NewBeaufort(4),
 			MaxTemp:          hkodata.NewTemperature(24.6),
 			MinTemp:          hkodata.NewTemperature(19.5),
 		},
@@ -39,8 +41,9 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "cch",
 			CurrentTemp:      hkodata.NewTemperature(22.0),
 			RelativeHumidity: hkodata.NewRelativeHumidity(.68),
-			WindDirection:    "SE",
+			WindDirection:    hkodata.NewWindDirection(hkodata.WindSouthEast),
 			WindSpeed:        hkodata.NewSpeed(30),
+			WindForce:        hkodata.NewBeaufort(5),
 			MaxTemp:          hkodata.NewTemperature(22.0),
 			MinTemp:          hkodata.NewTemperature(18.4),
 		},
@@ -49,7 +52,7 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "hpv",
 			CurrentTemp:      hkodata.NewTemperature(23.1),
 			RelativeHumidity: nil,
-			WindDirection:    "",
+			WindDirection:    nil,
 			WindSpeed:        nil,
 			MaxTemp:          hkodata.NewTemperature(23.1),
 			MinTemp:          hkodata.NewTemperature(17.1),
@@ -59,7 +62,7 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "hko",
 			CurrentTemp:      hkodata.NewTemperature(20.8),
 			RelativeHumidity: hkodata.NewRelativeHumidity(.74),
-			WindDirection:    "",
+			WindDirection:    nil,
 			WindSpeed:        nil,
 			MaxTemp:          hkodata.NewTemperature(20.8),
 			MinTemp:          hkodata.NewTemperature(18.5),
@@ -69,7 +72,7 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "hkp",
 			CurrentTemp:      hkodata.NewTemperature(21.6),
 			RelativeHumidity: nil,
-			WindDirection:    "",
+			WindDirection:    nil,
 			WindSpeed:        nil,
 			MaxTemp:          hkodata.NewTemperature(21.6),
 			MinTemp:          hkodata.NewTemperature(17.5),
@@ -79,7 +82,7 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "se1",
 			CurrentTemp:      hkodata.NewTemperature(21.8),
 			RelativeHumidity: nil,
-			WindDirection:    "",
+			WindDirection:    nil,
 			WindSpeed:        nil,
 			MaxTemp:          hkodata.NewTemperature(22.5),
 			MinTemp:          hkodata.NewTemperature(19.0),
@@ -89,7 +92,7 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "ksc",
 			CurrentTemp:      hkodata.NewTemperature(22.2),
 			RelativeHumidity: hkodata.NewRelativeHumidity(.66),
-			WindDirection:    "",
+			WindDirection:    nil,
 			WindSpeed:        nil,
 			MaxTemp:          hkodata.NewTemperature(22.2),
 			MinTemp:          hkodata.NewTemperature(15.5),
@@ -99,8 +102,9 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "kp",
 			CurrentTemp:      hkodata.NewTemperature(21.7),
 			RelativeHumidity: hkodata.NewRelativeHumidity(.66),
-			WindDirection:    "SE",
+			WindDirection:    hkodata.NewWindDirection(hkodata.WindSouthEast),
 			WindSpeed:        hkodata.NewSpeed(9),
+			WindForce:        hkodata.NewBeaufort(2),
 			MaxTemp:          hkodata.NewTemperature(21.8),
 			MinTemp:          hkodata.NewTemperature(17.7),
 		},
@@ -109,7 +113,7 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "klt",
 			CurrentTemp:      hkodata.NewTemperature(22.5),
 			RelativeHumidity: nil,
-			WindDirection:    "",
+			WindDirection:    nil,
 			WindSpeed:        nil,
 			MaxTemp:          hkodata.NewTemperature(23.2),
 			MinTemp:          hkodata.NewTemperature(17.8),
@@ -119,7 +123,7 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "ktg",
 			CurrentTemp:      hkodata.NewTemperature(21.9),
 			RelativeHumidity: nil,
-			WindDirection:    "",
+			WindDirection:    nil,
 			WindSpeed:        nil,
 			MaxTemp:          hkodata.NewTemperature(22.0),
 			MinTemp:          hkodata.NewTemperature(18.4),
@@ -129,8 +133,9 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "lfs",
 			CurrentTemp:      hkodata.NewTemperature(23.8),
 			RelativeHumidity: hkodata.NewRelativeHumidity(.62),
-			WindDirection:    "SE",
+			WindDirection:    hkodata.NewWindDirection(hkodata.WindSouthEast),
 			WindSpeed:        hkodata.NewSpeed(8),
+			WindForce:        hkodata.NewBeaufort(2),
 			MaxTemp:          hkodata.NewTemperature(23.8),
 			MinTemp:          hkodata.NewTemperature(15.9),
 		},
@@ -139,8 +144,9 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "ngp",
 			CurrentTemp:      hkodata.NewTemperature(19.3),
 			RelativeHumidity: nil,
-			WindDirection:    "E",
+			WindDirection:    hkodata.NewWindDirection(hkodata.WindEast),
 			WindSpeed:        hkodata.NewSpeed(39),
+			WindForce:        hkodata.NewBeaufort(6),
 			MaxTemp:          hkodata.NewTemperature(20.1),
 			MinTemp:          hkodata.NewTemperature(17.5),
 		},
@@ -149,7 +155,7 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "tyw",
 			CurrentTemp:      hkodata.NewTemperature(22.4),
 			RelativeHumidity: nil,
-			WindDirection:    "",
+			WindDirection:    nil,
 			WindSpeed:        nil,
 			MaxTemp:          hkodata.NewTemperature(22.4),
 			MinTemp:          hkodata.NewTemperature(12.4),
@@ -159,8 +165,9 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "pen",
 			CurrentTemp:      hkodata.NewTemperature(21.8),
 			RelativeHumidity: hkodata.NewRelativeHumidity(.72),
-			WindDirection:    "NE",
+			WindDirection:    hkodata.NewWindDirection(hkodata.WindNorthEast),
 			WindSpeed:        hkodata.NewSpeed(18),
+			WindForce:        hkodata.NewBeaufort(3),
 			MaxTemp:          hkodata.NewTemperature(21.8),
 			MinTemp:          hkodata.NewTemperature(18.7),
 		},
@@ -169,8 +176,9 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "skg",
 			CurrentTemp:      hkodata.NewTemperature(20.6),
 			RelativeHumidity: hkodata.NewRelativeHumidity(.72),
-			WindDirection:    "SE",
+			WindDirection:    hkodata.NewWindDirection(hkodata.WindSouthEast),
 			WindSpeed:        hkodata.NewSpeed(8),
+			WindForce:        hkodata.NewBeaufort(2),
 			MaxTemp:          hkodata.NewTemperature(20.6),
 			MinTemp:          hkodata.NewTemperature(16.6),
 		},
@@ -179,7 +187,7 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "ssp",
 			CurrentTemp:      hkodata.NewTemperature(24.1),
 			RelativeHumidity: nil,
-			WindDirection:    "",
+			WindDirection:    nil,
 			WindSpeed:        nil,
 			MaxTemp:          hkodata.NewTemperature(24.3),
 			MinTemp:          hkodata.NewTemperature(18.0),
@@ -189,8 +197,9 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "sha",
 			CurrentTemp:      hkodata.NewTemperature(22.9),
 			RelativeHumidity: hkodata.NewRelativeHumidity(.59),
-			WindDirection:    "SE",
+			WindDirection:    hkodata.NewWindDirection(hkodata.WindSouthEast),
 			WindSpeed:        hkodata.NewSpeed(5),
+			WindForce:        hkodata.NewBeaufort(1),
 			MaxTemp:          hkodata.NewTemperature(23.0),
 			MinTemp:          hkodata.NewTemperature(15.8),
 		},
@@ -199,7 +208,7 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "skw",
 			CurrentTemp:      hkodata.NewTemperature(21.2),
 			RelativeHumidity: nil,
-			WindDirection:    "",
+			WindDirection:    nil,
 			WindSpeed:        nil,
 			MaxTemp:          hkodata.NewTemperature(21.7),
 			MinTemp:          hkodata.NewTemperature(17.9),
@@ -209,7 +218,7 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "sek",
 			CurrentTemp:      hkodata.NewTemperature(22.9),
 			RelativeHumidity: hkodata.NewRelativeHumidity(.63),
-			WindDirection:    "",
+			WindDirection:    nil,
 			WindSpeed:        nil,
 			MaxTemp:          hkodata.NewTemperature(22.9),
 			MinTemp:          hkodata.NewTemperature(15.4),
@@ -219,7 +228,7 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "ssh",
 			CurrentTemp:      hkodata.NewTemperature(21.1),
 			RelativeHumidity: hkodata.NewRelativeHumidity(.70),
-			WindDirection:    "",
+			WindDirection:    nil,
 			WindSpeed:        nil,
 			MaxTemp:          hkodata.NewTemperature(21.1),
 			MinTemp:          hkodata.NewTemperature(16.1),
@@ -229,8 +238,9 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "sty",
 			CurrentTemp:      hkodata.NewTemperature(20.6),
 			RelativeHumidity: nil,
-			WindDirection:    "E",
+			WindDirection:    hkodata.NewWindDirection(hkodata.WindEast),
 			WindSpeed:        hkodata.NewSpeed(20),
+			WindForce:        hkodata.NewBeaufort(4),
 			MaxTemp:          hkodata.NewTemperature(20.7),
 			MinTemp:          hkodata.NewTemperature(18.6),
 		},
@@ -239,8 +249,9 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "tkl",
 			CurrentTemp:      hkodata.NewTemperature(21.9),
 			RelativeHumidity: hkodata.NewRelativeHumidity(.65),
-			WindDirection:    "E",
+			WindDirection:    hkodata.NewWindDirection(hkodata.WindEast),
 			WindSpeed:        hkodata.NewSpeed(10),
+			WindForce:        hkodata.NewBeaufort(2),
 			MaxTemp:          hkodata.NewTemperature(21.9),
 			MinTemp:          hkodata.NewTemperature(15.0),
 		},
@@ -249,7 +260,7 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "tms",
 			CurrentTemp:      hkodata.NewTemperature(16.4),
 			RelativeHumidity: nil,
-			WindDirection:    "",
+			WindDirection:    nil,
 			WindSpeed:        nil,
 			MaxTemp:          hkodata.NewTemperature(17.0),
 			MinTemp:          hkodata.NewTemperature(12.6),
@@ -259,7 +270,7 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "tpo",
 			CurrentTemp:      hkodata.NewTemperature(22.9),
 			RelativeHumidity: hkodata.NewRelativeHumidity(.68),
-			WindDirection:    "",
+			WindDirection:    nil,
 			WindSpeed:        nil,
 			MaxTemp:          hkodata.NewTemperature(22.9),
 			MinTemp:          hkodata.NewTemperature(16.7),
@@ -269,7 +280,7 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "vp1",
 			CurrentTemp:      hkodata.NewTemperature(20.4),
 			RelativeHumidity: nil,
-			WindDirection:    "",
+			WindDirection:    nil,
 			WindSpeed:        nil,
 			MaxTemp:          hkodata.NewTemperature(20.4),
 			MinTemp:          hkodata.NewTemperature(15.5),
@@ -279,8 +290,9 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "jkb",
 			CurrentTemp:      hkodata.NewTemperature(22.3),
 			RelativeHumidity: hkodata.NewRelativeHumidity(.67),
-			WindDirection:    "E",
+			WindDirection:    hkodata.NewWindDirection(hkodata.WindEast),
 			WindSpeed:        hkodata.NewSpeed(8),
+			WindForce:        hkodata.NewBeaufort(2),
 			MaxTemp:          hkodata.NewTemperature(22.6),
 			MinTemp:          hkodata.NewTemperature(17.0),
 		},
@@ -289,8 +301,9 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "ty1",
 			CurrentTemp:      hkodata.NewTemperature(22.1),
 			RelativeHumidity: hkodata.NewRelativeHumidity(.61),
-			WindDirection:    "E",
+			WindDirection:    hkodata.NewWindDirection(hkodata.WindEast),
 			WindSpeed:        hkodata.NewSpeed(8),
+			WindForce:        hkodata.NewBeaufort(2),
 			MaxTemp:          hkodata.NewTemperature(22.1),
 			MinTemp:          hkodata.NewTemperature(16.6),
 		},
@@ -299,7 +312,7 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "twn",
 			CurrentTemp:      hkodata.NewTemperature(21.9),
 			RelativeHumidity: hkodata.NewRelativeHumidity(.64),
-			WindDirection:    "",
+			WindDirection:    nil,
 			WindSpeed:        nil,
 			MaxTemp:          hkodata.NewTemperature(21.9),
 			MinTemp:          hkodata.NewTemperature(16.1),
@@ -309,7 +322,7 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "tw",
 			CurrentTemp:      hkodata.NewTemperature(22.8),
 			RelativeHumidity: hkodata.NewRelativeHumidity(.63),
-			WindDirection:    "",
+			WindDirection:    nil,
 			WindSpeed:        nil,
 			MaxTemp:          hkodata.NewTemperature(22.8),
 			MinTemp:          hkodata.NewTemperature(17.6),
@@ -319,8 +332,9 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "tun",
 			CurrentTemp:      hkodata.NewTemperature(23.8),
 			RelativeHumidity: hkodata.NewRelativeHumidity(.58),
-			WindDirection:    "S",
+			WindDirection:    hkodata.NewWindDirection(hkodata.WindSouth),
 			WindSpeed:        hkodata.NewSpeed(8),
+			WindForce:        hkodata.NewBeaufort(2),
 			MaxTemp:          hkodata.NewTemperature(24.3),
 			MinTemp:          hkodata.NewTemperature(17.9),
 		},
@@ -329,8 +343,9 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "wgl",
 			CurrentTemp:      hkodata.NewTemperature(22.4),
 			RelativeHumidity: hkodata.NewRelativeHumidity(.72),
-			WindDirection:    "E",
+			WindDirection:    hkodata.NewWindDirection(hkodata.WindEast),
 			WindSpeed:        hkodata.NewSpeed(23),
+			WindForce:        hkodata.NewBeaufort(4),
 			MaxTemp:          hkodata.NewTemperature(22.7),
 			MinTemp:          hkodata.NewTemperature(18.6),
 		},
@@ -339,8 +354,9 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "wlp",
 			CurrentTemp:      hkodata.NewTemperature(23.6),
 			RelativeHumidity: hkodata.NewRelativeHumidity(.59),
-			WindDirection:    "NE",
+			WindDirection:    hkodata.NewWindDirection(hkodata.WindNorthEast),
 			WindSpeed:        hkodata.NewSpeed(6),
+			WindForce:        hkodata.NewBeaufort(2),
 			MaxTemp:          hkodata.NewTemperature(24.0),
 			MinTemp:          hkodata.NewTemperature(15.3),
 		},
@@ -349,8 +365,9 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "hks",
 			CurrentTemp:      hkodata.NewTemperature(22.3),
 			RelativeHumidity: hkodata.NewRelativeHumidity(.62),
-			WindDirection:    "NE",
+			WindDirection:    hkodata.NewWindDirection(hkodata.WindNorthEast),
 			WindSpeed:        hkodata.NewSpeed(14),
+			WindForce:        hkodata.NewBeaufort(3),
 			MaxTemp:          hkodata.NewTemperature(22.3),
 			MinTemp:          hkodata.NewTemperature(17.2),
 		},
@@ -359,7 +376,7 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "wts",
 			CurrentTemp:      hkodata.NewTemperature(23.6),
 			RelativeHumidity: nil,
-			WindDirection:    "",
+			WindDirection:    nil,
 			WindSpeed:        nil,
 			MaxTemp:          hkodata.NewTemperature(23.6),
 			MinTemp:          hkodata.NewTemperature(17.5),
@@ -369,7 +386,7 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "ylp",
 			CurrentTemp:      hkodata.NewTemperature(23.8),
 			RelativeHumidity: nil,
-			WindDirection:    "",
+			WindDirection:    nil,
 			WindSpeed:        nil,
 			MaxTemp:          hkodata.NewTemperature(23.8),
 			MinTemp:          hkodata.NewTemperature(15.1),
@@ -379,8 +396,9 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "tc",
 			CurrentTemp:      hkodata.NewTemperature(17.5),
 			RelativeHumidity: nil,
-			WindDirection:    "E",
+			WindDirection:    hkodata.NewWindDirection(hkodata.WindEast),
 			WindSpeed:        hkodata.NewSpeed(28),
+			WindForce:        hkodata.NewBeaufort(4),
 			MaxTemp:          hkodata.NewTemperature(17.6),
 			MinTemp:          hkodata.NewTemperature(14.0),
 		},
@@ -389,8 +407,9 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "gi",
 			CurrentTemp:      nil,
 			RelativeHumidity: nil,
-			WindDirection:    "NE",
+			WindDirection:    hkodata.NewWindDirection(hkodata.WindNorthEast),
 			WindSpeed:        hkodata.NewSpeed(29),
+			WindForce:        hkodata.NewBeaufort(5),
 			MaxTemp:          nil,
 			MinTemp:          nil,
 		},
@@ -399,8 +418,9 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "se",
 			CurrentTemp:      nil,
 			RelativeHumidity: nil,
-			WindDirection:    "E",
+			WindDirection:    hkodata.NewWindDirection(hkodata.WindEast),
 			WindSpeed:        hkodata.NewSpeed(17),
+			WindForce:        hkodata.NewBeaufort(3),
 			MaxTemp:          nil,
 			MinTemp:          nil,
 		},
@@ -409,8 +429,9 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "sc",
 			CurrentTemp:      nil,
 			RelativeHumidity: nil,
-			WindDirection:    "E",
+			WindDirection:    hkodata.NewWindDirection(hkodata.WindEast),
 			WindSpeed:        hkodata.NewSpeed(18),
+			WindForce:        hkodata.NewBeaufort(3),
 			MaxTemp:          nil,
 			MinTemp:          nil,
 		},
@@ -419,8 +440,9 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "sf",
 			CurrentTemp:      nil,
 			RelativeHumidity: nil,
-			WindDirection:    "E",
+			WindDirection:    hkodata.NewWindDirection(hkodata.WindEast),
 			WindSpeed:        hkodata.NewSpeed(16),
+			WindForce:        hkodata.NewBeaufort(3),
 			MaxTemp:          nil,
 			MinTemp:          nil,
 		},
@@ -429,8 +451,9 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "plc",
 			CurrentTemp:      nil,
 			RelativeHumidity: nil,
-			WindDirection:    "E",
+			WindDirection:    hkodata.NewWindDirection(hkodata.WindEast),
 			WindSpeed:        hkodata.NewSpeed(11),
+			WindForce:        hkodata.NewBeaufort(2),
 			MaxTemp:          nil,
 			MinTemp:          nil,
 		},
@@ -439,8 +462,9 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "tpk",
 			CurrentTemp:      nil,
 			RelativeHumidity: nil,
-			WindDirection:    "E",
+			WindDirection:    hkodata.NewWindDirection(hkodata.WindEast),
 			WindSpeed:        hkodata.NewSpeed(11),
+			WindForce:        hkodata.NewBeaufort(2),
 			MaxTemp:          nil,
 			MinTemp:          nil,
 		},
@@ -449,8 +473,9 @@ func TestDecodeRegionJSON(t *testing.T) {
 			ShortName:        "tap",
 			CurrentTemp:      nil,
 			RelativeHumidity: nil,
-			WindDirection:    "SE",
+			WindDirection:    hkodata.NewWindDirection(hkodata.WindSouthEast),
 			WindSpeed:        hkodata.NewSpeed(20),
+			WindForce:        hkodata.NewBeaufort(4),
 			MaxTemp:          nil,
 			MinTemp:          nil,
 		},
@@ -503,8 +528,9 @@ func TestDecodeRegionJSON_dummyCase(t *testing.T) {
 			ShortName:        "hka",
 			CurrentTemp:      hkodata.NewTemperature(24.1),
 			RelativeHumidity: hkodata.NewRelativeHumidity(.54),
-			WindDirection:    "E",
+			WindDirection:    hkodata.NewWindDirection(hkodata.WindEast),
 			WindSpeed:        hkodata.NewSpeed(24),
+			WindForce:        hkodata.NewBeaufort(4),
 			MaxTemp:          hkodata.NewTemperature(24.6),
 			MinTemp:          hkodata.NewTemperature(19.5),
 		},
@@ -513,17 +539,24 @@ func TestDecodeRegionJSON_dummyCase(t *testing.T) {
 			ShortName:        "cch",
 			CurrentTemp:      hkodata.NewTemperature(0),
 			RelativeHumidity: hkodata.NewRelativeHumidity(0),
-			WindDirection:    "SE",
+			WindDirection:    hkodata.NewWindDirection(hkodata.WindSouthEast),
 			WindSpeed:        hkodata.NewSpeed(0),
 			MaxTemp:          hkodata.NewTemperature(0),
 			MinTemp:          hkodata.NewTemperature(0),
+			Quality: hkodata.Quality{
+				"CurrentTemp":      {hkodata.QualityPlaceholder},
+				"RelativeHumidity": {hkodata.QualityPlaceholder},
+				"WindSpeed":        {hkodata.QualityPlaceholder},
+				"MaxTemp":          {hkodata.QualityPlaceholder},
+				"MinTemp":          {hkodata.QualityPlaceholder},
+			},
 		},
 		hkodata.Region{
 			Name:             hkodata.RegionName("hpv"),
 			ShortName:        "hpv",
 			CurrentTemp:      nil,
 			RelativeHumidity: nil,
-			WindDirection:    "",
+			WindDirection:    nil,
 			WindSpeed:        nil,
 			MaxTemp:          nil,
 			MinTemp:          nil,
@@ -533,10 +566,17 @@ func TestDecodeRegionJSON_dummyCase(t *testing.T) {
 			ShortName:        "hks",
 			CurrentTemp:      hkodata.NewTemperature(-10),
 			RelativeHumidity: hkodata.NewRelativeHumidity(-.1),
-			WindDirection:    "",
+			WindDirection:    nil,
 			WindSpeed:        hkodata.NewSpeed(-10),
 			MaxTemp:          hkodata.NewTemperature(-10),
 			MinTemp:          hkodata.NewTemperature(-10),
+			Quality: hkodata.Quality{
+				"CurrentTemp":      {hkodata.QualityOutOfRange},
+				"RelativeHumidity": {hkodata.QualityOutOfRange},
+				"WindSpeed":        {hkodata.QualityOutOfRange},
+				"MaxTemp":          {hkodata.QualityOutOfRange},
+				"MinTemp":          {hkodata.QualityOutOfRange},
+			},
 		},
 	}
 
@@ -563,3 +603,82 @@ func TestDecodeRegionJSON_dummyCase(t *testing.T) {
 	}
 
 }
+
+func TestDecodeRegionJSON_warnings(t *testing.T) {
+	str := `{"btime":"201612191020","fields":["region","temp","rh"],"datas":[["hka","24.1","54"],["cch","N/A","68"],["hko","20.8","74","extra"]]}`
+
+	regions, err := hkodata.DecodeRegionJSON(strings.NewReader(str))
+	if regions == nil {
+		t.Fatalf("expected partial result, got nil")
+	}
+	parseErrors, ok := err.(hkodata.ParseError)
+	if !ok {
+		t.Fatalf("expected hkodata.ParseError, got %#v", err)
+	}
+	warnings := parseErrors.Warnings()
+	if want, have := 2, len(warnings); want != have {
+		t.Fatalf("expected %#v, got %#v (%s)", want, have, parseErrors)
+	}
+	if want, have := "datas[1].temp", warnings[0].Field; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := "N/A", warnings[0].Raw; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := 3, len(regions.Regions); want != have {
+		t.Fatalf("expected %#v, got %#v", want, have)
+	}
+	if regions.Regions[1].CurrentTemp != nil {
+		t.Errorf("expected nil, got %#v", *regions.Regions[1].CurrentTemp)
+	}
+	if want, have := hkodata.NewTemperature(20.8), regions.Regions[2].CurrentTemp; !reflect.DeepEqual(want, have) {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	bytes, _ := json.Marshal(warnings[0])
+	if want, have := `{"severity":"warning","field":"datas[1].temp","raw":"N/A","message":"unable to parse number"}`, string(bytes); want != have {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+
+	// strict mode discards the partial result
+	regions, err = hkodata.DecodeRegionJSON(strings.NewReader(str), hkodata.Strict())
+	if regions != nil {
+		t.Errorf("expected nil, got %#v", regions)
+	}
+	if err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestDecodeRegionJSON_badBulletinTime(t *testing.T) {
+	str := `{"btime":"someday","fields":["region","temp"],"datas":[["hka","24.1"]]}`
+
+	regions, err := hkodata.DecodeRegionJSON(strings.NewReader(str))
+	if regions != nil {
+		t.Errorf("expected nil, got %#v", regions)
+	}
+	if parseErrors, ok := err.(hkodata.ParseError); !ok {
+		t.Errorf("expected hkodata.ParseError, got %#v", err)
+	} else if want, have := true, parseErrors.Fatal(); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
+
+func TestDecodeRegionJSON_invalid(t *testing.T) {
+	regions, err := hkodata.DecodeRegionJSON(strings.NewReader("<html>Service Unavailable</html>"))
+	if regions != nil {
+		t.Errorf("expected nil, got %#v", regions)
+	}
+	parseErrors, ok := err.(hkodata.ParseError)
+	if !ok {
+		t.Fatalf("expected hkodata.ParseError, got %#v", err)
+	}
+	if want, have := true, parseErrors.Fatal(); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if fieldErr, ok := parseErrors[0].(*hkodata.FieldError); !ok {
+		t.Errorf("expected *hkodata.FieldError, got %#v", parseErrors[0])
+	} else if want, have := "<html>Service Unavailable</html>", fieldErr.Raw; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
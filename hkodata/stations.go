@@ -0,0 +1,468 @@
+package hkodata
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// StationType represents the type of a weather station
+type StationType int
+
+const (
+	// StationAutomatic represents automatic weather station
+	StationAutomatic StationType = iota
+
+	// StationHeadquarters represents the HKO headquarters
+	StationHeadquarters
+
+	// StationWindOnly represents anemometer station that only
+	// reports wind direction and speed
+	StationWindOnly
+)
+
+// String implements fmt.Stringer
+func (typ StationType) String() string {
+	switch typ {
+	case StationHeadquarters:
+		return "headquarters"
+	case StationWindOnly:
+		return "wind-only"
+	}
+	return "automatic"
+}
+
+// MarshalJSON implements json.Marshaler
+func (typ StationType) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(typ.String())), nil
+}
+
+// Station contains metadata of a weather station
+type Station struct {
+	ID        string // HKO shortname (e.g. "kp")
+	Name      I18nName
+	Latitude  float64
+	Longitude float64
+	Elevation float64 // metres above mean sea level
+	District  I18nName
+	Type      StationType
+	Aliases   []string // other names used in HKO reports
+}
+
+var stations map[string]Station
+
+// stationIDs maps normalized names and aliases of stations to
+// station ID (HKO shortname)
+var stationIDs map[string]string
+
+// normalizeStationName normalizes a name for alias lookup
+// (e.g. "King's Park" and "KingsPark" both become "kingspark")
+func normalizeStationName(name string) string {
+	return strings.ToLower(reNonWord.ReplaceAllString(name, ""))
+}
+
+// LookupStation finds a station by its ID (HKO shortname)
+func LookupStation(id string) (station Station, ok bool) {
+	station, ok = stations[id]
+	return
+}
+
+// LookupStationByAlias finds a station by its ID, English or Chinese name
+// or any known alias. Space and punctuation are ignored, so names in
+// HKO reports (e.g. "King's Park") and the keys of DistrictsTemperature
+// (e.g. "KingsPark") can both be used.
+func LookupStationByAlias(alias string) (station Station, ok bool) {
+	if station, ok = stations[alias]; ok {
+		return
+	}
+	id, ok := stationIDs[normalizeStationName(alias)]
+	if !ok {
+		id, ok = stationIDs[strings.TrimSpace(alias)]
+	}
+	if ok {
+		station = stations[id]
+	}
+	return
+}
+
+// Stations returns all the stations known, ordered by ID
+func Stations() []Station {
+	list := make([]Station, 0, len(stations))
+	for _, station := range stations {
+		list = append(list, station)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// districts of Hong Kong
+var (
+	districtCentralAndWestern = I18nName{Zh: "中西區", En: "Central and Western"}
+	districtWanChai           = I18nName{Zh: "灣仔區", En: "Wan Chai"}
+	districtEastern           = I18nName{Zh: "東區", En: "Eastern"}
+	districtSouthern          = I18nName{Zh: "南區", En: "Southern"}
+	districtYauTsimMong       = I18nName{Zh: "油尖旺區", En: "Yau Tsim Mong"}
+	districtShamShuiPo        = I18nName{Zh: "深水埗區", En: "Sham Shui Po"}
+	districtKowloonCity       = I18nName{Zh: "九龍城區", En: "Kowloon City"}
+	districtWongTaiSin        = I18nName{Zh: "黃大仙區", En: "Wong Tai Sin"}
+	districtKwunTong          = I18nName{Zh: "觀塘區", En: "Kwun Tong"}
+	districtKwaiTsing         = I18nName{Zh: "葵青區", En: "Kwai Tsing"}
+	districtTsuenWan          = I18nName{Zh: "荃灣區", En: "Tsuen Wan"}
+	districtTuenMun           = I18nName{Zh: "屯門區", En: "Tuen Mun"}
+	districtYuenLong          = I18nName{Zh: "元朗區", En: "Yuen Long"}
+	districtNorth             = I18nName{Zh: "北區", En: "North"}
+	districtTaiPo             = I18nName{Zh: "大埔區", En: "Tai Po"}
+	districtShaTin            = I18nName{Zh: "沙田區", En: "Sha Tin"}
+	districtSaiKung           = I18nName{Zh: "西貢區", En: "Sai Kung"}
+	districtIslands           = I18nName{Zh: "離島區", En: "Islands"}
+)
+
+func init() {
+	// note: coordinates and elevations are approximate
+	stations = map[string]Station{
+		"cch": {
+			Latitude:  22.2011,
+			Longitude: 114.0267,
+			Elevation: 72,
+			District:  districtIslands,
+			Type:      StationAutomatic,
+		},
+		"cen": {
+			Latitude:  22.2875,
+			Longitude: 114.1556,
+			Elevation: 9,
+			District:  districtCentralAndWestern,
+			Type:      StationWindOnly,
+			Aliases:   []string{"Central"},
+		},
+		"gi": {
+			Latitude:  22.2850,
+			Longitude: 114.1128,
+			Elevation: 88,
+			District:  districtCentralAndWestern,
+			Type:      StationWindOnly,
+		},
+		"hka": {
+			Latitude:  22.3094,
+			Longitude: 113.9219,
+			Elevation: 6,
+			District:  districtIslands,
+			Type:      StationAutomatic,
+			Aliases:   []string{"Hong Kong International Airport", "Airport"},
+		},
+		"hko": {
+			Latitude:  22.3019,
+			Longitude: 114.1742,
+			Elevation: 32,
+			District:  districtYauTsimMong,
+			Type:      StationHeadquarters,
+			Aliases:   []string{"HK Observatory", "HKO"},
+		},
+		"hkp": {
+			Latitude:  22.2783,
+			Longitude: 114.1622,
+			Elevation: 26,
+			District:  districtCentralAndWestern,
+			Type:      StationAutomatic,
+		},
+		"hks": {
+			Latitude:  22.2478,
+			Longitude: 114.1736,
+			Elevation: 5,
+			District:  districtSouthern,
+			Type:      StationAutomatic,
+		},
+		"hpv": {
+			Latitude:  22.2706,
+			Longitude: 114.1836,
+			Elevation: 33,
+			District:  districtWanChai,
+			Type:      StationAutomatic,
+		},
+		"jkb": {
+			Latitude:  22.3158,
+			Longitude: 114.2556,
+			Elevation: 38,
+			District:  districtSaiKung,
+			Type:      StationAutomatic,
+		},
+		"klt": {
+			Latitude:  22.3350,
+			Longitude: 114.1847,
+			Elevation: 8,
+			District:  districtKowloonCity,
+			Type:      StationAutomatic,
+		},
+		"kp": {
+			Latitude:  22.3119,
+			Longitude: 114.1728,
+			Elevation: 65,
+			District:  districtYauTsimMong,
+			Type:      StationAutomatic,
+			Aliases:   []string{"Kings Park"},
+		},
+		"ksc": {
+			Latitude:  22.3703,
+			Longitude: 114.3125,
+			Elevation: 47,
+			District:  districtSaiKung,
+			Type:      StationAutomatic,
+		},
+		"ktg": {
+			Latitude:  22.3147,
+			Longitude: 114.2256,
+			Elevation: 40,
+			District:  districtKwunTong,
+			Type:      StationAutomatic,
+		},
+		"lfs": {
+			Latitude:  22.4689,
+			Longitude: 113.9836,
+			Elevation: 31,
+			District:  districtYuenLong,
+			Type:      StationAutomatic,
+		},
+		"ngp": {
+			Latitude:  22.2586,
+			Longitude: 113.9128,
+			Elevation: 593,
+			District:  districtIslands,
+			Type:      StationAutomatic,
+		},
+		"pen": {
+			Latitude:  22.2911,
+			Longitude: 114.0433,
+			Elevation: 34,
+			District:  districtIslands,
+			Type:      StationAutomatic,
+		},
+		"plc": {
+			Latitude:  22.4753,
+			Longitude: 114.2375,
+			Elevation: 45,
+			District:  districtTaiPo,
+			Type:      StationWindOnly,
+		},
+		"sc": {
+			Latitude:  22.3458,
+			Longitude: 113.8911,
+			Elevation: 31,
+			District:  districtIslands,
+			Type:      StationWindOnly,
+		},
+		"se": {
+			Latitude:  22.3097,
+			Longitude: 114.2133,
+			Elevation: 5,
+			District:  districtKowloonCity,
+			Type:      StationWindOnly,
+		},
+		"se1": {
+			Latitude:  22.3047,
+			Longitude: 114.2169,
+			Elevation: 3,
+			District:  districtKowloonCity,
+			Type:      StationAutomatic,
+		},
+		"sek": {
+			Latitude:  22.4361,
+			Longitude: 114.0847,
+			Elevation: 16,
+			District:  districtYuenLong,
+			Type:      StationAutomatic,
+		},
+		"sf": {
+			Latitude:  22.2933,
+			Longitude: 114.1689,
+			Elevation: 9,
+			District:  districtYauTsimMong,
+			Type:      StationWindOnly,
+		},
+		"sha": {
+			Latitude:  22.4025,
+			Longitude: 114.2100,
+			Elevation: 6,
+			District:  districtShaTin,
+			Type:      StationAutomatic,
+			Aliases:   []string{"Shatin"},
+		},
+		"skg": {
+			Latitude:  22.3756,
+			Longitude: 114.2744,
+			Elevation: 2,
+			District:  districtSaiKung,
+			Type:      StationAutomatic,
+		},
+		"skw": {
+			Latitude:  22.2817,
+			Longitude: 114.2361,
+			Elevation: 46,
+			District:  districtEastern,
+			Type:      StationAutomatic,
+		},
+		"ssh": {
+			Latitude:  22.5019,
+			Longitude: 114.1111,
+			Elevation: 10,
+			District:  districtNorth,
+			Type:      StationAutomatic,
+		},
+		"ssp": {
+			Latitude:  22.3358,
+			Longitude: 114.1369,
+			Elevation: 11,
+			District:  districtShamShuiPo,
+			Type:      StationAutomatic,
+		},
+		"sty": {
+			Latitude:  22.2142,
+			Longitude: 114.2188,
+			Elevation: 31,
+			District:  districtSouthern,
+			Type:      StationAutomatic,
+		},
+		"swh": {
+			Latitude:  22.2822,
+			Longitude: 114.2217,
+			Elevation: 20,
+			District:  districtEastern,
+			Type:      StationAutomatic,
+		},
+		"tap": {
+			Latitude:  22.4714,
+			Longitude: 114.3608,
+			Elevation: 15,
+			District:  districtTaiPo,
+			Type:      StationWindOnly,
+		},
+		"tc": {
+			Latitude:  22.3578,
+			Longitude: 114.2178,
+			Elevation: 572,
+			District:  districtShaTin,
+			Type:      StationAutomatic,
+		},
+		"tkl": {
+			Latitude:  22.5286,
+			Longitude: 114.1567,
+			Elevation: 15,
+			District:  districtNorth,
+			Type:      StationAutomatic,
+		},
+		"tms": {
+			Latitude:  22.4108,
+			Longitude: 114.1244,
+			Elevation: 955,
+			District:  districtTsuenWan,
+			Type:      StationAutomatic,
+		},
+		"tpk": {
+			Latitude:  22.4428,
+			Longitude: 114.1839,
+			Elevation: 11,
+			District:  districtTaiPo,
+			Type:      StationWindOnly,
+		},
+		"tpo": {
+			Latitude:  22.4461,
+			Longitude: 114.1789,
+			Elevation: 5,
+			District:  districtTaiPo,
+			Type:      StationAutomatic,
+		},
+		"tun": {
+			Latitude:  22.3858,
+			Longitude: 113.9642,
+			Elevation: 69,
+			District:  districtTuenMun,
+			Type:      StationAutomatic,
+		},
+		"tw": {
+			Latitude:  22.3756,
+			Longitude: 114.1267,
+			Elevation: 34,
+			District:  districtTsuenWan,
+			Type:      StationAutomatic,
+			Aliases:   []string{"Shing Mun Valley"},
+		},
+		"twn": {
+			Latitude:  22.3836,
+			Longitude: 114.1078,
+			Elevation: 148,
+			District:  districtTsuenWan,
+			Type:      StationAutomatic,
+			Aliases:   []string{"Ho Koon"},
+		},
+		"ty1": {
+			Latitude:  22.3442,
+			Longitude: 114.1103,
+			Elevation: 8,
+			District:  districtKwaiTsing,
+			Type:      StationAutomatic,
+		},
+		"tyw": {
+			Latitude:  22.4028,
+			Longitude: 114.3231,
+			Elevation: 5,
+			District:  districtSaiKung,
+			Type:      StationAutomatic,
+		},
+		"vp1": {
+			Latitude:  22.2642,
+			Longitude: 114.1550,
+			Elevation: 397,
+			District:  districtCentralAndWestern,
+			Type:      StationAutomatic,
+			Aliases:   []string{"Victoria Peak", "Peak"},
+		},
+		"wgl": {
+			Latitude:  22.1822,
+			Longitude: 114.3033,
+			Elevation: 56,
+			District:  districtIslands,
+			Type:      StationAutomatic,
+		},
+		"wlp": {
+			Latitude:  22.4667,
+			Longitude: 114.0089,
+			Elevation: 4,
+			District:  districtYuenLong,
+			Type:      StationAutomatic,
+			Aliases:   []string{"Hong Kong Wetland Park"},
+		},
+		"wts": {
+			Latitude:  22.3394,
+			Longitude: 114.2053,
+			Elevation: 22,
+			District:  districtWongTaiSin,
+			Type:      StationAutomatic,
+		},
+		"ylp": {
+			Latitude:  22.4408,
+			Longitude: 114.0183,
+			Elevation: 6,
+			District:  districtYuenLong,
+			Type:      StationAutomatic,
+		},
+	}
+
+	// fill in the ID and names, and index the names and aliases
+	stationIDs = make(map[string]string, len(stations)*3)
+	for id, station := range stations {
+		station.ID = id
+		station.Name = regionNames[id]
+		stations[id] = station
+
+		if station.Name.En != "" {
+			stationIDs[normalizeStationName(station.Name.En)] = id
+		}
+		if station.Name.Zh != "" {
+			stationIDs[station.Name.Zh] = id
+		}
+		for _, alias := range station.Aliases {
+			stationIDs[normalizeStationName(alias)] = id
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package hkodata_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/yookoala/weatherhk/hkodata"
+)
+
+func TestLookupStationByAlias(t *testing.T) {
+	tests := []struct {
+		alias string
+		id    string
+	}{
+		{"kp", "kp"},
+		{"King's Park", "kp"},
+		{"KingsPark", "kp"},
+		{"京士柏", "kp"},
+		{"Hong Kong International Airport", "hka"},
+		{"TsuenWanShingMunValley", "tw"},
+		{"Kai Tak Runway Park", "se1"},
+	}
+	for _, test := range tests {
+		station, ok := hkodata.LookupStationByAlias(test.alias)
+		if !ok {
+			t.Errorf("%#v: station not found", test.alias)
+			continue
+		}
+		if want, have := test.id, station.ID; want != have {
+			t.Errorf("%#v: expected %#v, got %#v", test.alias, want, have)
+		}
+	}
+
+	if _, ok := hkodata.LookupStationByAlias("Some New Place"); ok {
+		t.Errorf("expected station not found")
+	}
+}
+
+func TestStations(t *testing.T) {
+	stations := hkodata.Stations()
+	for i, station := range stations {
+		if i > 0 && stations[i-1].ID >= station.ID {
+			t.Errorf("stations not ordered by ID: %#v before %#v", stations[i-1].ID, station.ID)
+		}
+		if station.Name != hkodata.RegionName(station.ID) {
+			t.Errorf("%s: expected %#v, got %#v", station.ID, hkodata.RegionName(station.ID), station.Name)
+		}
+		if station.Name.En == "" || station.Name.Zh == "" {
+			t.Errorf("%s: name is incomplete: %#v", station.ID, station.Name)
+		}
+		if station.District.En == "" || station.District.Zh == "" {
+			t.Errorf("%s: district is incomplete: %#v", station.ID, station.District)
+		}
+		if station.Latitude < 22.1 || station.Latitude > 22.6 || station.Longitude < 113.8 || station.Longitude > 114.5 {
+			t.Errorf("%s: coordinates out of Hong Kong: (%v, %v)", station.ID, station.Latitude, station.Longitude)
+		}
+	}
+
+	hko, ok := hkodata.LookupStation("hko")
+	if !ok {
+		t.Fatalf("station hko not found")
+	}
+	if want, have := hkodata.StationHeadquarters, hko.Type; want != have {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+	bytes, _ := json.Marshal(hko.Type)
+	if want, have := `"headquarters"`, string(bytes); want != have {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+}
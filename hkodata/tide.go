@@ -0,0 +1,133 @@
+package hkodata
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TideType represents the type of a tide event
+type TideType int
+
+const (
+	// TideUnknown represents unidentified tide type
+	TideUnknown TideType = iota
+
+	// TideHigh represents high water
+	TideHigh
+
+	// TideLow represents low water
+	TideLow
+)
+
+// String implements fmt.Stringer
+func (typ TideType) String() string {
+	switch typ {
+	case TideHigh:
+		return "high"
+	case TideLow:
+		return "low"
+	}
+	return "unknown"
+}
+
+// MarshalJSON implements json.Marshaler
+func (typ TideType) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(typ.String())), nil
+}
+
+// parseTideType parses the Chinese label of tide type in HKO data
+// (e.g. "漲潮", "退潮﹕")
+func parseTideType(label string) TideType {
+	label = strings.TrimRight(strings.TrimSpace(label), ":：﹕ ")
+	switch label {
+	case "漲潮":
+		return TideHigh
+	case "退潮":
+		return TideLow
+	}
+	return TideUnknown
+}
+
+// parseClock parses a "hh:mm" clock time string on the given date.
+// Hour of 24 or beyond is treated as time of the following day(s).
+func parseClock(date time.Time, str string) (t time.Time, err error) {
+	var hour, minute int
+	if _, err = fmt.Sscanf(strings.TrimSpace(str), "%d:%d", &hour, &minute); err != nil {
+		err = fmt.Errorf("unidentified clock time: %#v", str)
+		return
+	}
+	if hour < 0 || minute < 0 || minute > 59 {
+		err = fmt.Errorf("clock time out of range: %#v", str)
+		return
+	}
+	year, month, day := date.Date()
+	t = time.Date(year, month, day, hour, minute, 0, 0, date.Location())
+	return
+}
+
+// TideEvent represents a high or low water event
+type TideEvent struct {
+	Type   TideType
+	Time   time.Time
+	Height float64 // height of water in metres
+}
+
+// TideTable contains the tide events of a day
+type TideTable struct {
+	Date   time.Time
+	Events []TideEvent
+}
+
+// Expires implements Expirer interface
+// (the table is for a single date)
+func (table TideTable) Expires() time.Time {
+	return table.Date.AddDate(0, 0, 1)
+}
+
+// TideTable parses the tide array in CMN section into TideTable
+func (one OneJSON) TideTable(opts ...DecodeOption) (table *TideTable, err error) {
+	cmn := one.CMN
+	parseErrors := make(ParseError, 0, 10)
+
+	table = &TideTable{
+		Events: make([]TideEvent, 0, len(cmn.Tide)),
+	}
+	if table.Date, err = time.ParseInLocation("20060102", cmn.GregorianDate, HKT); err != nil {
+		parseErrors.fatal("CMN.GregorianDate", cmn.GregorianDate, "unable to parse date")
+		return nil, parseErrors
+	}
+
+	var last time.Time
+	for i, tide := range cmn.Tide {
+		field := fmt.Sprintf("CMN.Tide[%d]", i)
+		event := TideEvent{
+			Type:   parseTideType(tide.Type),
+			Height: parseFloat(field+".Height", tide.Height, &parseErrors),
+		}
+		if event.Type == TideUnknown {
+			parseErrors.warn(field+".Type", tide.Type, "unknown tide type")
+		}
+
+		if event.Time, err = parseClock(table.Date, tide.Time); err != nil {
+			parseErrors.warn(field+".Time", tide.Time, "%s", err)
+			continue
+		}
+
+		// tide events are listed chronologically, so going backward in
+		// time means crossing midnight into the next day
+		for event.Time.Before(last) {
+			event.Time = event.Time.AddDate(0, 0, 1)
+		}
+		last = event.Time
+
+		table.Events = append(table.Events, event)
+	}
+
+	err = parseErrors.result(newDecodeOptions(opts))
+	if parseErrors.Fatal() {
+		table = nil
+	}
+	return
+}
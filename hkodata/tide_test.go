@@ -0,0 +1,78 @@
+package hkodata_test
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	pretty "github.com/tonnerre/golang-pretty"
+	"github.com/yookoala/weatherhk/hkodata"
+)
+
+func TestOneJSON_TideTable(t *testing.T) {
+	file, err := os.Open("./test/one_json_uc.201612191917.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	defer file.Close()
+
+	one, err := hkodata.DecodeOneJSON(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	table, err := one.TideTable()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if want, have := time.Date(2016, time.December, 20, 0, 0, 0, 0, hkodata.HKT), table.Expires(); !want.Equal(have) {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+
+	expected := []hkodata.TideEvent{
+		{Type: hkodata.TideHigh, Time: time.Date(2016, time.December, 19, 0, 22, 0, 0, hkodata.HKT), Height: 2.3},
+		{Type: hkodata.TideLow, Time: time.Date(2016, time.December, 19, 7, 27, 0, 0, hkodata.HKT), Height: 0.6},
+		{Type: hkodata.TideHigh, Time: time.Date(2016, time.December, 19, 14, 52, 0, 0, hkodata.HKT), Height: 1.6},
+		{Type: hkodata.TideLow, Time: time.Date(2016, time.December, 19, 18, 17, 0, 0, hkodata.HKT), Height: 1.4},
+	}
+	if want, have := expected, table.Events; !reflect.DeepEqual(want, have) {
+		t.Errorf("unexpected difference in tide events (want != have)")
+		for _, desc := range pretty.Diff(want, have) {
+			t.Log("\tevents" + desc)
+		}
+	}
+
+	bytes, _ := json.Marshal(table.Events[0])
+	if want, have := `{"Type":"high","Time":"2016-12-19T00:22:00+08:00","Height":2.3}`, string(bytes); want != have {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+}
+
+func TestOneJSON_TideTable_crossMidnight(t *testing.T) {
+	one := hkodata.OneJSON{
+		CMN: hkodata.OneJSONCMN{
+			GregorianDate: "20161219",
+			Tide: []hkodata.OneJSONTide{
+				{Type: "漲潮", Time: "18:30", Height: "2.1"},
+				{Type: "退潮", Time: "01:05", Height: "0.9"},
+				{Type: "大潮", Time: "06:00", Height: "1.5"},
+			},
+		},
+	}
+
+	table, err := one.TideTable()
+	if _, ok := err.(hkodata.ParseError); !ok {
+		t.Errorf("expected hkodata.ParseError, got %#v", err)
+	}
+	if want, have := 3, len(table.Events); want != have {
+		t.Fatalf("expected %#v, got %#v", want, have)
+	}
+	if want, have := time.Date(2016, time.December, 20, 1, 5, 0, 0, hkodata.HKT), table.Events[1].Time; !want.Equal(have) {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+	if want, have := hkodata.TideUnknown, table.Events[2].Type; want != have {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+}
@@ -0,0 +1,232 @@
+package hkodata
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// Celsius returns the temperature in degree celcius
+func (temp Temperature) Celsius() float64 {
+	return float64(temp)
+}
+
+// Fahrenheit returns the temperature in degree fahrenheit
+func (temp Temperature) Fahrenheit() float64 {
+	return float64(temp)*9/5 + 32
+}
+
+// Kelvin returns the temperature in kelvin
+func (temp Temperature) Kelvin() float64 {
+	return float64(temp) + 273.15
+}
+
+// KilometresPerHour returns the speed in km/h
+func (speed Speed) KilometresPerHour() float64 {
+	return float64(speed)
+}
+
+// MetresPerSecond returns the speed in m/s
+func (speed Speed) MetresPerSecond() float64 {
+	return float64(speed) / 3.6
+}
+
+// Knots returns the speed in knots
+func (speed Speed) Knots() float64 {
+	return float64(speed) / 1.852
+}
+
+// MilesPerHour returns the speed in mph
+func (speed Speed) MilesPerHour() float64 {
+	return float64(speed) / 1.609344
+}
+
+// beaufortLimits are the lower limits (in km/h) of force 1 to 12 in
+// the Beaufort scale, as used by HKO
+var beaufortLimits = []float64{2, 6, 12, 20, 29, 39, 50, 62, 75, 89, 103, 118}
+
+// Beaufort returns the force of wind of the speed in the Beaufort scale
+func (speed Speed) Beaufort() Beaufort {
+	for force, limit := range beaufortLimits {
+		if float64(speed) < limit {
+			return Beaufort(force)
+		}
+	}
+	return Beaufort(len(beaufortLimits))
+}
+
+// Fraction returns the relative humidity as a fraction (0.5 = 50%)
+func (rh RelativeHumidity) Fraction() float64 {
+	return float64(rh)
+}
+
+// Percent returns the relative humidity in percent
+func (rh RelativeHumidity) Percent() float64 {
+	return float64(rh) * 100
+}
+
+// UnitSystem represents the system of units to present data in
+type UnitSystem int
+
+const (
+	// UnitsMetric presents temperature in degree celcius and speed in km/h
+	// (the units in HKO data)
+	UnitsMetric UnitSystem = iota
+
+	// UnitsImperial presents temperature in degree fahrenheit and speed
+	// in mph
+	UnitsImperial
+
+	// UnitsSI presents temperature in kelvin and speed in m/s
+	UnitsSI
+)
+
+// ParseUnitSystem parses the name of a unit system
+// (i.e. "metric", "imperial" or "si")
+func ParseUnitSystem(str string) (system UnitSystem, err error) {
+	switch str {
+	case "metric":
+		return UnitsMetric, nil
+	case "imperial":
+		return UnitsImperial, nil
+	case "si":
+		return UnitsSI, nil
+	}
+	err = fmt.Errorf("unknown unit system: %#v", str)
+	return
+}
+
+// String implements fmt.Stringer
+func (system UnitSystem) String() string {
+	switch system {
+	case UnitsImperial:
+		return "imperial"
+	case UnitsSI:
+		return "si"
+	}
+	return "metric"
+}
+
+// MarshalJSON implements json.Marshaler
+func (system UnitSystem) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(system.String())), nil
+}
+
+// Units describes the units of values presented in a unit system
+type Units struct {
+	System           UnitSystem `json:"system"`
+	Temperature      string     `json:"temperature"`
+	Speed            string     `json:"speed"`
+	RelativeHumidity string     `json:"relative_humidity"`
+}
+
+// Units returns the units of values presented in the unit system.
+// Relative humidity is always presented as fraction.
+func (system UnitSystem) Units() Units {
+	switch system {
+	case UnitsImperial:
+		return Units{system, "°F", "mph", "fraction"}
+	case UnitsSI:
+		return Units{system, "K", "m/s", "fraction"}
+	}
+	return Units{system, "°C", "km/h", "fraction"}
+}
+
+var temperatureType = reflect.TypeOf(Temperature(0))
+var speedType = reflect.TypeOf(Speed(0))
+
+// roundUnit rounds converted values to 2 decimal places to avoid
+// floating point noise in the presentation
+func roundUnit(val float64) float64 {
+	return math.Round(val*100) / 100
+}
+
+// ConvertUnits converts, in place, all Temperature and Speed values
+// reachable from v (a pointer) into the unit system. The values are no
+// longer in their documented units afterwards, so this should only be
+// used on data about to be presented (e.g. encoded as JSON).
+func ConvertUnits(v interface{}, system UnitSystem) {
+	if system == UnitsMetric {
+		return
+	}
+	converter := unitConverter{
+		system:  system,
+		visited: make(map[visitedPointer]bool),
+	}
+	converter.convert(reflect.ValueOf(v))
+}
+
+type visitedPointer struct {
+	typ reflect.Type
+	ptr uintptr
+}
+
+type unitConverter struct {
+	system  UnitSystem
+	visited map[visitedPointer]bool
+}
+
+func (converter unitConverter) convert(v reflect.Value) {
+	switch v.Type() {
+	case temperatureType:
+		if v.CanSet() {
+			temp := Temperature(v.Float())
+			if converter.system == UnitsImperial {
+				v.SetFloat(roundUnit(temp.Fahrenheit()))
+			} else {
+				v.SetFloat(roundUnit(temp.Kelvin()))
+			}
+		}
+		return
+	case speedType:
+		if v.CanSet() {
+			speed := Speed(v.Float())
+			if converter.system == UnitsImperial {
+				v.SetFloat(roundUnit(speed.MilesPerHour()))
+			} else {
+				v.SetFloat(roundUnit(speed.MetresPerSecond()))
+			}
+		}
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		// the same value might be referenced more than once
+		key := visitedPointer{v.Type(), v.Pointer()}
+		if converter.visited[key] {
+			return
+		}
+		converter.visited[key] = true
+		converter.convert(v.Elem())
+	case reflect.Interface:
+		if v.IsNil() || !v.CanSet() {
+			return
+		}
+		elem := reflect.New(v.Elem().Type()).Elem()
+		elem.Set(v.Elem())
+		converter.convert(elem)
+		v.Set(elem)
+	case reflect.Struct:
+		for i, numField := 0, v.NumField(); i < numField; i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				converter.convert(v.Field(i))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i, length := 0, v.Len(); i < length; i++ {
+			converter.convert(v.Index(i))
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(v.MapIndex(key))
+			converter.convert(elem)
+			v.SetMapIndex(key, elem)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package hkodata_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/yookoala/weatherhk/hkodata"
+)
+
+func TestTemperature_conversion(t *testing.T) {
+	temp := hkodata.Temperature(100)
+	if want, have := 212.0, temp.Fahrenheit(); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := 373.15, temp.Kelvin(); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := -40.0, hkodata.Temperature(-40).Fahrenheit(); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
+
+func TestSpeed_conversion(t *testing.T) {
+	speed := hkodata.Speed(36)
+	if want, have := 10.0, speed.MetresPerSecond(); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := 19.438, speed.Knots(); math.Abs(want-have) > 0.001 {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := 22.369, speed.MilesPerHour(); math.Abs(want-have) > 0.001 {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	tests := []struct {
+		speed hkodata.Speed
+		force hkodata.Beaufort
+	}{
+		{0, 0},
+		{1, 0},
+		{2, 1},
+		{24, 4},
+		{39, 6},
+		{117, 11},
+		{118, 12},
+		{200, 12},
+	}
+	for _, test := range tests {
+		if want, have := test.force, test.speed.Beaufort(); want != have {
+			t.Errorf("%v km/h: expected force %d, got %d", test.speed, want, have)
+		}
+	}
+}
+
+func TestRelativeHumidity_conversion(t *testing.T) {
+	if want, have := 54.0, hkodata.RelativeHumidity(.54).Percent(); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
+
+func TestParseUnitSystem(t *testing.T) {
+	for _, name := range []string{"metric", "imperial", "si"} {
+		system, err := hkodata.ParseUnitSystem(name)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+		}
+		if want, have := name, system.String(); want != have {
+			t.Errorf("expected %#v, got %#v", want, have)
+		}
+	}
+	if _, err := hkodata.ParseUnitSystem("nautical"); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestConvertUnits(t *testing.T) {
+	temp := hkodata.NewTemperature(20)
+	regions := hkodata.Regions{
+		Regions: []hkodata.Region{
+			{ShortName: "hko", CurrentTemp: temp, WindSpeed: hkodata.NewSpeed(36)},
+		},
+	}
+	nearby := hkodata.NearbyConditions{
+		Conditions: hkodata.Region{CurrentTemp: temp},
+		Stations: []hkodata.NearbyRegion{
+			{Region: regions.Regions[0], Distance: 1.5},
+		},
+	}
+	cw := hkodata.CurrentWeather{
+		AirTemperature:   0,
+		RelativeHumidity: .54,
+		Stations: map[string]hkodata.StationTemperature{
+			"hko": {ID: "hko", Temperature: 10},
+		},
+	}
+
+	// data in interface, as in the server response
+	var data interface{} = cw
+	hkodata.ConvertUnits(&nearby, hkodata.UnitsImperial)
+	hkodata.ConvertUnits(&data, hkodata.UnitsImperial)
+
+	// shared pointer converted only once
+	if want, have := hkodata.Temperature(68), *nearby.Conditions.CurrentTemp; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := hkodata.Speed(22.37), *nearby.Stations[0].WindSpeed; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := 1.5, nearby.Stations[0].Distance; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	converted := data.(hkodata.CurrentWeather)
+	if want, have := hkodata.Temperature(32), converted.AirTemperature; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := hkodata.Temperature(50), converted.Stations["hko"].Temperature; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := hkodata.RelativeHumidity(.54), converted.RelativeHumidity; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	bytes, _ := json.Marshal(hkodata.UnitsSI.Units())
+	if want, have := `{"system":"si","temperature":"K","speed":"m/s","relative_humidity":"fraction"}`, string(bytes); want != have {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+}
@@ -0,0 +1,201 @@
+package hkodata
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UVIndex contains the ultraviolet index
+type UVIndex float64
+
+// NewUVIndex generates a pointer to UVIndex value
+func NewUVIndex(num float64) *UVIndex {
+	val := UVIndex(num)
+	return &val
+}
+
+// UVCategory represents the exposure category of UV index
+// (WHO bands)
+type UVCategory int
+
+const (
+	// UVNotAvailable represents the category of an unavailable UV index
+	UVNotAvailable UVCategory = iota
+
+	// UVLow represents UV index 0 to 2
+	UVLow
+
+	// UVModerate represents UV index 3 to 5
+	UVModerate
+
+	// UVHigh represents UV index 6 to 7
+	UVHigh
+
+	// UVVeryHigh represents UV index 8 to 10
+	UVVeryHigh
+
+	// UVExtreme represents UV index 11 or above
+	UVExtreme
+)
+
+var uvCategoryNames = map[UVCategory]I18nName{
+	UVLow:      {Zh: "低", En: "low"},
+	UVModerate: {Zh: "中等", En: "moderate"},
+	UVHigh:     {Zh: "高", En: "high"},
+	UVVeryHigh: {Zh: "甚高", En: "very high"},
+	UVExtreme:  {Zh: "極高", En: "extreme"},
+}
+
+// UVCategoryOf returns the WHO exposure category of the UV index
+func UVCategoryOf(index UVIndex) UVCategory {
+	// UV index is reported in integer, round before categorize
+	switch rounded := int(float64(index) + 0.5); {
+	case rounded < 0:
+		return UVNotAvailable
+	case rounded <= 2:
+		return UVLow
+	case rounded <= 5:
+		return UVModerate
+	case rounded <= 7:
+		return UVHigh
+	case rounded <= 10:
+		return UVVeryHigh
+	}
+	return UVExtreme
+}
+
+// parseUVCategory parses category label of HKO data (e.g. "中等")
+func parseUVCategory(label string) UVCategory {
+	label = strings.TrimSpace(label)
+	for category, name := range uvCategoryNames {
+		if label == name.Zh || strings.EqualFold(label, name.En) {
+			return category
+		}
+	}
+	return UVNotAvailable
+}
+
+// Name returns the name of the category
+func (category UVCategory) Name() I18nName {
+	return uvCategoryNames[category]
+}
+
+// String implements fmt.Stringer
+func (category UVCategory) String() string {
+	if name, ok := uvCategoryNames[category]; ok {
+		return name.En
+	}
+	return "not available"
+}
+
+// MarshalJSON implements json.Marshaler
+func (category UVCategory) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(category.String())), nil
+}
+
+// parseUVIndex parses UV index string of HKO data. Placeholder of
+// missing data (e.g. "//") will result in nil.
+func parseUVIndex(str string) (index *UVIndex, err error) {
+	str = strings.TrimSpace(str)
+	if str == "" || strings.Trim(str, "/-") == "" {
+		return
+	}
+	val, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		err = fmt.Errorf("unidentified UV index: %#v", str)
+		return
+	}
+	index = NewUVIndex(val)
+	return
+}
+
+// UVForecast contains the forecast of maximum UV index of a date
+type UVForecast struct {
+	PubDate  time.Time
+	Date     time.Time
+	MaxIndex *UVIndex
+	Category UVCategory
+	Message  string
+}
+
+// Expires implements Expirer interface
+// (the forecast is issued daily without fixed schedule)
+func (forecast UVForecast) Expires() time.Time {
+	return forecast.PubDate.Add(60 * time.Minute)
+}
+
+// UVReading contains the UV index reading of the past hour. Index is nil
+// if the reading is not available (e.g. at night).
+type UVReading struct {
+	PubDate  time.Time
+	Index    *UVIndex
+	Category UVCategory
+}
+
+// Expires implements Expirer interface
+// (reading is updated hourly)
+func (reading UVReading) Expires() time.Time {
+	return reading.PubDate.Add(60 * time.Minute)
+}
+
+// UVForecast parses the FUV section into UVForecast
+func (one OneJSON) UVForecast(opts ...DecodeOption) (forecast *UVForecast, err error) {
+	fuv := one.FUV
+	parseErrors := make(ParseError, 0, 4)
+
+	forecast = &UVForecast{
+		Message: fuv.Message,
+	}
+	if forecast.PubDate, err = time.ParseInLocation("200601021504", fuv.BulletinDate+fuv.BulletinTime, HKT); err != nil {
+		parseErrors.fatal("FUV.BulletinTime", fuv.BulletinDate+fuv.BulletinTime, "unable to parse bulletin time")
+	}
+	if forecast.Date, err = time.ParseInLocation("20060102", fuv.ForecastTimeInfoDate, HKT); err != nil {
+		parseErrors.warn("FUV.ForecastTimeInfoDate", fuv.ForecastTimeInfoDate, "unable to parse date")
+	}
+	if forecast.MaxIndex, err = parseUVIndex(fuv.ForecastTimeInfoMaxUV); err != nil {
+		parseErrors.warn("FUV.ForecastTimeInfoMaxUV", fuv.ForecastTimeInfoMaxUV, "%s", err)
+	}
+
+	// categorize by the index, and check against the category given
+	if forecast.MaxIndex != nil {
+		forecast.Category = UVCategoryOf(*forecast.MaxIndex)
+		if label := fuv.ForecastTimeInfoMaxUvCategory; label != "" && parseUVCategory(label) != forecast.Category {
+			parseErrors.warn("FUV.ForecastTimeInfoMaxUvCategory", label, "does not match index %v", *forecast.MaxIndex)
+		}
+	} else {
+		forecast.Category = parseUVCategory(fuv.ForecastTimeInfoMaxUvCategory)
+	}
+
+	err = parseErrors.result(newDecodeOptions(opts))
+	if parseErrors.Fatal() {
+		forecast = nil
+	}
+	return
+}
+
+// UVReading parses the UV index in RHRREAD section into UVReading
+func (one OneJSON) UVReading(opts ...DecodeOption) (reading *UVReading, err error) {
+	rhrread := one.RHRREAD
+	parseErrors := make(ParseError, 0, 2)
+
+	reading = &UVReading{}
+	if reading.PubDate, err = time.ParseInLocation("200601021504", rhrread.BulletinDate+rhrread.BulletinTime, HKT); err != nil {
+		parseErrors.fatal("RHRREAD.BulletinTime", rhrread.BulletinDate+rhrread.BulletinTime, "unable to parse bulletin time")
+	}
+	if reading.Index, err = parseUVIndex(rhrread.UVIndex); err != nil {
+		parseErrors.warn("RHRREAD.UVIndex", rhrread.UVIndex, "%s", err)
+	}
+	if reading.Index != nil {
+		reading.Category = UVCategoryOf(*reading.Index)
+	} else {
+		reading.Category = parseUVCategory(rhrread.Intensity)
+	}
+
+	err = parseErrors.result(newDecodeOptions(opts))
+	if parseErrors.Fatal() {
+		reading = nil
+	}
+	return
+}
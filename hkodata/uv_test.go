@@ -0,0 +1,110 @@
+package hkodata_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/yookoala/weatherhk/hkodata"
+)
+
+func TestUVCategoryOf(t *testing.T) {
+	tests := []struct {
+		index    hkodata.UVIndex
+		category hkodata.UVCategory
+	}{
+		{0, hkodata.UVLow},
+		{2, hkodata.UVLow},
+		{3, hkodata.UVModerate},
+		{5, hkodata.UVModerate},
+		{6, hkodata.UVHigh},
+		{7, hkodata.UVHigh},
+		{8, hkodata.UVVeryHigh},
+		{10, hkodata.UVVeryHigh},
+		{11, hkodata.UVExtreme},
+		{15, hkodata.UVExtreme},
+	}
+	for _, test := range tests {
+		if want, have := test.category, hkodata.UVCategoryOf(test.index); want != have {
+			t.Errorf("index %v: expected %s, got %s", test.index, want, have)
+		}
+	}
+}
+
+func TestOneJSON_UVForecast(t *testing.T) {
+	file, err := os.Open("./test/one_json_uc.201612191917.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	defer file.Close()
+
+	one, err := hkodata.DecodeOneJSON(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	forecast, err := one.UVForecast()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if want, have := time.Date(2016, time.December, 19, 17, 0, 0, 0, hkodata.HKT), forecast.PubDate; !want.Equal(have) {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+	if want, have := time.Date(2016, time.December, 20, 0, 0, 0, 0, hkodata.HKT), forecast.Date; !want.Equal(have) {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+	if forecast.MaxIndex == nil {
+		t.Fatalf("expected MaxIndex, got nil")
+	}
+	if want, have := hkodata.UVIndex(5), *forecast.MaxIndex; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := hkodata.UVModerate, forecast.Category; want != have {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+	if want, have := "中等", forecast.Category.Name().Zh; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	reading, err := one.UVReading()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if reading.Index != nil {
+		t.Errorf("expected nil, got %#v", *reading.Index)
+	}
+	if want, have := hkodata.UVNotAvailable, reading.Category; want != have {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+
+	bytes, _ := json.Marshal(reading)
+	if want, have := `{"PubDate":"2016-12-19T19:02:00+08:00","Index":null,"Category":"not available"}`, string(bytes); want != have {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+}
+
+func TestOneJSON_UVReading(t *testing.T) {
+	one := hkodata.OneJSON{
+		RHRREAD: hkodata.OneJSONRHRREAD{
+			BulletinDate: "20160719",
+			BulletinTime: "1302",
+			UVIndex:      "9",
+			Intensity:    "甚高",
+		},
+	}
+
+	reading, err := one.UVReading()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if reading.Index == nil {
+		t.Fatalf("expected Index, got nil")
+	}
+	if want, have := hkodata.UVIndex(9), *reading.Index; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := hkodata.UVVeryHigh, reading.Category; want != have {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+}
@@ -0,0 +1,155 @@
+package hkodata
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WeatherTipType represents the type of a special weather tip
+type WeatherTipType int
+
+const (
+	// TipHeadline represents headline of special weather tips
+	TipHeadline WeatherTipType = iota
+
+	// TipSMS represents special weather tips for SMS
+	TipSMS
+
+	// TipTornado represents tornado report
+	TipTornado
+
+	// TipWaterspout represents waterspout report
+	TipWaterspout
+
+	// TipGust represents gust forecast
+	TipGust
+
+	// TipHotAdvisory represents hot weather advisory
+	TipHotAdvisory
+)
+
+// String implements fmt.Stringer
+func (typ WeatherTipType) String() string {
+	switch typ {
+	case TipHeadline:
+		return "headline"
+	case TipSMS:
+		return "sms"
+	case TipTornado:
+		return "tornado"
+	case TipWaterspout:
+		return "waterspout"
+	case TipGust:
+		return "gust"
+	case TipHotAdvisory:
+		return "hot_advisory"
+	}
+	return "unknown"
+}
+
+// MarshalJSON implements json.Marshaler
+func (typ WeatherTipType) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(typ.String())), nil
+}
+
+// WeatherTip represents a special weather tip in effect
+type WeatherTip struct {
+	Type    WeatherTipType
+	Message string
+}
+
+// SpecialWeatherTips contains special weather tips and advisories.
+// Tips not in effect are nil.
+type SpecialWeatherTips struct {
+	PubDate          time.Time
+	Headlines        []string
+	SMS              *string `json:"SMS,omitempty"`
+	TornadoReport    *string `json:"TornadoReport,omitempty"`
+	WaterspoutReport *string `json:"WaterspoutReport,omitempty"`
+	GustForecast     *string `json:"GustForecast,omitempty"`
+	HotAdvisory      *string `json:"HotAdvisory,omitempty"`
+}
+
+// Expires implements Expirer interface
+func (tips SpecialWeatherTips) Expires() time.Time {
+	return tips.PubDate.Add(10 * time.Minute)
+}
+
+// Active returns all tips in effect
+func (tips SpecialWeatherTips) Active() ActiveWeatherTips {
+	active := ActiveWeatherTips{
+		PubDate: tips.PubDate,
+		Tips:    make([]WeatherTip, 0, len(tips.Headlines)+5),
+	}
+	for _, headline := range tips.Headlines {
+		active.Tips = append(active.Tips, WeatherTip{Type: TipHeadline, Message: headline})
+	}
+
+	fields := []struct {
+		typ WeatherTipType
+		msg *string
+	}{
+		{TipSMS, tips.SMS},
+		{TipTornado, tips.TornadoReport},
+		{TipWaterspout, tips.WaterspoutReport},
+		{TipGust, tips.GustForecast},
+		{TipHotAdvisory, tips.HotAdvisory},
+	}
+	for _, field := range fields {
+		if field.msg != nil {
+			active.Tips = append(active.Tips, WeatherTip{Type: field.typ, Message: *field.msg})
+		}
+	}
+	return active
+}
+
+// ActiveWeatherTips contains special weather tips in effect
+type ActiveWeatherTips struct {
+	PubDate time.Time
+	Tips    []WeatherTip
+}
+
+// Expires implements Expirer interface
+func (active ActiveWeatherTips) Expires() time.Time {
+	return active.PubDate.Add(10 * time.Minute)
+}
+
+// optionalString normalises empty string to nil
+func optionalString(str string) *string {
+	if str = strings.TrimSpace(str); str == "" {
+		return nil
+	}
+	return &str
+}
+
+// SpecialWeatherTips parses the SWT section into SpecialWeatherTips
+func (one OneJSON) SpecialWeatherTips(opts ...DecodeOption) (tips *SpecialWeatherTips, err error) {
+	swt := one.SWT
+	parseErrors := make(ParseError, 0, 1)
+
+	// the SWT section has no time of its own
+	if one.PubDate.IsZero() {
+		parseErrors.warn("currwx.btime", one.CurrWx.BulletinTime, "unknown publish time of tips")
+	}
+	tips = &SpecialWeatherTips{
+		PubDate:          one.PubDate,
+		Headlines:        make([]string, 0, 5),
+		SMS:              optionalString(swt.SMSSWT),
+		TornadoReport:    optionalString(swt.TornadoReport),
+		WaterspoutReport: optionalString(swt.WaterspoutReport),
+		GustForecast:     optionalString(swt.GustForecast),
+		HotAdvisory:      optionalString(swt.HotAdvisory),
+	}
+	for _, headline := range []string{swt.HeadLine1, swt.HeadLine2, swt.HeadLine3, swt.HeadLine4, swt.HeadLine5} {
+		if str := optionalString(headline); str != nil {
+			tips.Headlines = append(tips.Headlines, *str)
+		}
+	}
+
+	err = parseErrors.result(newDecodeOptions(opts))
+	if parseErrors.Fatal() {
+		tips = nil
+	}
+	return
+}
@@ -0,0 +1,101 @@
+package hkodata_test
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	pretty "github.com/tonnerre/golang-pretty"
+	"github.com/yookoala/weatherhk/hkodata"
+)
+
+func TestOneJSON_SpecialWeatherTips(t *testing.T) {
+	file, err := os.Open("./test/one_json_uc.201612191917.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	defer file.Close()
+
+	one, err := hkodata.DecodeOneJSON(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	tips, err := one.SpecialWeatherTips()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if want, have := 0, len(tips.Headlines); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if tips.HotAdvisory != nil {
+		t.Errorf("expected nil, got %#v", *tips.HotAdvisory)
+	}
+	if want, have := 0, len(tips.Active().Tips); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	bytes, _ := json.Marshal(tips.Active())
+	if want, have := `{"PubDate":"2016-12-19T19:00:00+08:00","Tips":[]}`, string(bytes); want != have {
+		t.Errorf("expected %s, got %s", want, have)
+	}
+}
+
+func TestOneJSON_SpecialWeatherTips_active(t *testing.T) {
+	one := hkodata.OneJSON{
+		PubDate: time.Date(2016, time.December, 19, 19, 0, 0, 0, hkodata.HKT),
+		SWT: hkodata.OneJSONSWT{
+			HeadLine1:   "",
+			HeadLine2:   "炎熱天氣警告現正生效。",
+			HeadLine3:   "  ",
+			HotAdvisory: "天氣酷熱，市民應避免長時間在戶外活動。",
+		},
+	}
+
+	tips, err := one.SpecialWeatherTips()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if tips.SMS != nil {
+		t.Errorf("expected nil, got %#v", *tips.SMS)
+	}
+
+	expected := []hkodata.WeatherTip{
+		{Type: hkodata.TipHeadline, Message: "炎熱天氣警告現正生效。"},
+		{Type: hkodata.TipHotAdvisory, Message: "天氣酷熱，市民應避免長時間在戶外活動。"},
+	}
+	if want, have := expected, tips.Active().Tips; !reflect.DeepEqual(want, have) {
+		t.Errorf("unexpected difference in tips (want != have)")
+		for _, desc := range pretty.Diff(want, have) {
+			t.Log("\ttips" + desc)
+		}
+	}
+}
+
+func TestOneJSON_SpecialWeatherTips_noPubDate(t *testing.T) {
+	one := hkodata.OneJSON{
+		SWT: hkodata.OneJSONSWT{
+			HeadLine1: "炎熱天氣警告現正生效。",
+		},
+	}
+
+	tips, err := one.SpecialWeatherTips()
+	if tips == nil {
+		t.Fatalf("expected tips, got nil")
+	}
+	if parseErrors, ok := err.(hkodata.ParseError); !ok {
+		t.Errorf("expected hkodata.ParseError, got %#v", err)
+	} else if want, have := 1, len(parseErrors.Warnings()); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	// strict mode discards the partial result
+	if tips, err = one.SpecialWeatherTips(hkodata.Strict()); tips != nil {
+		t.Errorf("expected nil, got %#v", tips)
+	}
+	if err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
@@ -0,0 +1,188 @@
+package hkodata
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// WindDirection represents the direction wind blows from, in 16 compass
+// points, or calm and variable wind
+type WindDirection int
+
+// compass points of wind direction, clockwise from north
+const (
+	WindNorth WindDirection = iota
+	WindNorthNorthEast
+	WindNorthEast
+	WindEastNorthEast
+	WindEast
+	WindEastSouthEast
+	WindSouthEast
+	WindSouthSouthEast
+	WindSouth
+	WindSouthSouthWest
+	WindSouthWest
+	WindWestSouthWest
+	WindWest
+	WindWestNorthWest
+	WindNorthWest
+	WindNorthNorthWest
+
+	// WindCalm represents no wind
+	WindCalm
+
+	// WindVariable represents wind of variable direction
+	WindVariable
+)
+
+var windDirectionCodes = []string{
+	"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE",
+	"S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW",
+	"CALM", "VRB",
+}
+
+var windDirectionNames = []I18nName{
+	{Zh: "北", En: "North"},
+	{Zh: "北東北", En: "North-northeast"},
+	{Zh: "東北", En: "Northeast"},
+	{Zh: "東東北", En: "East-northeast"},
+	{Zh: "東", En: "East"},
+	{Zh: "東東南", En: "East-southeast"},
+	{Zh: "東南", En: "Southeast"},
+	{Zh: "南東南", En: "South-southeast"},
+	{Zh: "南", En: "South"},
+	{Zh: "南西南", En: "South-southwest"},
+	{Zh: "西南", En: "Southwest"},
+	{Zh: "西西南", En: "West-southwest"},
+	{Zh: "西", En: "West"},
+	{Zh: "西西北", En: "West-northwest"},
+	{Zh: "西北", En: "Northwest"},
+	{Zh: "北西北", En: "North-northwest"},
+	{Zh: "無風", En: "Calm"},
+	{Zh: "風向不定", En: "Variable"},
+}
+
+// NewWindDirection generates a pointer to WindDirection value
+func NewWindDirection(dir WindDirection) *WindDirection {
+	return &dir
+}
+
+// ParseWindDirection parses wind direction in HKO data (e.g. "SE"),
+// case insensitively. "Calm" and "Variable" (or "VRB") are supported.
+func ParseWindDirection(str string) (dir WindDirection, err error) {
+	code := strings.ToUpper(strings.TrimSpace(str))
+	if code == "VARIABLE" {
+		code = "VRB"
+	}
+	for i, known := range windDirectionCodes {
+		if code == known {
+			return WindDirection(i), nil
+		}
+	}
+	err = fmt.Errorf("unidentified wind direction: %#v", str)
+	return
+}
+
+// WindDirectionOf returns the nearest compass point of a direction
+// (in degrees clockwise from north)
+func WindDirectionOf(degrees float64) WindDirection {
+	point := int(math.Floor(math.Mod(degrees, 360)/22.5+0.5)) % 16
+	if point < 0 {
+		point += 16
+	}
+	return WindDirection(point)
+}
+
+// Code returns the abbreviation of the direction (e.g. "SE")
+func (dir WindDirection) Code() string {
+	if dir < 0 || int(dir) >= len(windDirectionCodes) {
+		return ""
+	}
+	return windDirectionCodes[dir]
+}
+
+// Name returns the name of the direction
+func (dir WindDirection) Name() I18nName {
+	if dir < 0 || int(dir) >= len(windDirectionNames) {
+		return I18nName{}
+	}
+	return windDirectionNames[dir]
+}
+
+// Degrees returns the direction in degrees clockwise from north. It is
+// not ok for calm and variable wind.
+func (dir WindDirection) Degrees() (degrees float64, ok bool) {
+	if dir < WindNorth || dir > WindNorthNorthWest {
+		return 0, false
+	}
+	return float64(dir) * 22.5, true
+}
+
+// String implements fmt.Stringer
+func (dir WindDirection) String() string {
+	return dir.Code()
+}
+
+// MarshalJSON implements json.Marshaler
+func (dir WindDirection) MarshalJSON() ([]byte, error) {
+	data := struct {
+		Code    string
+		Degrees *float64 `json:",omitempty"`
+		Name    I18nName
+	}{
+		Code: dir.Code(),
+		Name: dir.Name(),
+	}
+	if degrees, ok := dir.Degrees(); ok {
+		data.Degrees = &degrees
+	}
+	return json.Marshal(data)
+}
+
+// Beaufort represents the force of wind in the Beaufort scale
+type Beaufort int
+
+// NewBeaufort generates a pointer to Beaufort value
+func NewBeaufort(force int) *Beaufort {
+	val := Beaufort(force)
+	return &val
+}
+
+// beaufortDescriptions are the wind force terms used in HKO forecasts
+// (e.g. "和緩" for force 3 to 4)
+var beaufortDescriptions = []I18nName{
+	{Zh: "無風", En: "Calm"},
+	{Zh: "輕微", En: "Light"},
+	{Zh: "輕微", En: "Light"},
+	{Zh: "和緩", En: "Moderate"},
+	{Zh: "和緩", En: "Moderate"},
+	{Zh: "清勁", En: "Fresh"},
+	{Zh: "強風", En: "Strong"},
+	{Zh: "強風", En: "Strong"},
+	{Zh: "烈風", En: "Gale"},
+	{Zh: "烈風", En: "Gale"},
+	{Zh: "暴風", En: "Storm"},
+	{Zh: "暴風", En: "Storm"},
+	{Zh: "颶風", En: "Hurricane"},
+}
+
+// Description returns the description of the force
+func (force Beaufort) Description() I18nName {
+	if force < 0 || int(force) >= len(beaufortDescriptions) {
+		return I18nName{}
+	}
+	return beaufortDescriptions[force]
+}
+
+// MarshalJSON implements json.Marshaler
+func (force Beaufort) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Force       int
+		Description I18nName
+	}{
+		Force:       int(force),
+		Description: force.Description(),
+	})
+}
@@ -0,0 +1,116 @@
+package hkodata_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/yookoala/weatherhk/hkodata"
+)
+
+func TestParseWindDirection(t *testing.T) {
+	tests := []struct {
+		str     string
+		dir     hkodata.WindDirection
+		degrees float64
+		ok      bool
+	}{
+		{"N", hkodata.WindNorth, 0, true},
+		{"NNE", hkodata.WindNorthNorthEast, 22.5, true},
+		{"SE", hkodata.WindSouthEast, 135, true},
+		{"wsw", hkodata.WindWestSouthWest, 247.5, true},
+		{"NNW", hkodata.WindNorthNorthWest, 337.5, true},
+		{"Calm", hkodata.WindCalm, 0, false},
+		{"Variable", hkodata.WindVariable, 0, false},
+		{"VRB", hkodata.WindVariable, 0, false},
+	}
+	for _, test := range tests {
+		dir, err := hkodata.ParseWindDirection(test.str)
+		if err != nil {
+			t.Errorf("unexpected error parsing %#v: %s", test.str, err)
+			continue
+		}
+		if want, have := test.dir, dir; want != have {
+			t.Errorf("%#v: expected %s, got %s", test.str, want, have)
+		}
+		degrees, ok := dir.Degrees()
+		if want, have := test.ok, ok; want != have {
+			t.Errorf("%#v: expected ok to be %#v, got %#v", test.str, want, have)
+		}
+		if want, have := test.degrees, degrees; want != have {
+			t.Errorf("%#v: expected %#v degrees, got %#v", test.str, want, have)
+		}
+	}
+
+	if _, err := hkodata.ParseWindDirection("NORTH-ISH"); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestWindDirectionOf(t *testing.T) {
+	tests := []struct {
+		degrees float64
+		dir     hkodata.WindDirection
+	}{
+		{0, hkodata.WindNorth},
+		{11, hkodata.WindNorth},
+		{12, hkodata.WindNorthNorthEast},
+		{350, hkodata.WindNorth},
+		{-90, hkodata.WindWest},
+		{225, hkodata.WindSouthWest},
+		{720 + 90, hkodata.WindEast},
+	}
+	for _, test := range tests {
+		if want, have := test.dir, hkodata.WindDirectionOf(test.degrees); want != have {
+			t.Errorf("%#v: expected %s, got %s", test.degrees, want, have)
+		}
+	}
+}
+
+func TestWindDirection_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		dir  hkodata.WindDirection
+		json string
+	}{
+		{hkodata.WindSouthEast, `{"Code":"SE","Degrees":135,"Name":{"zh_HK":"東南","en":"Southeast"}}`},
+		{hkodata.WindNorth, `{"Code":"N","Degrees":0,"Name":{"zh_HK":"北","en":"North"}}`},
+		{hkodata.WindCalm, `{"Code":"CALM","Name":{"zh_HK":"無風","en":"Calm"}}`},
+	}
+	for _, test := range tests {
+		b, err := json.Marshal(test.dir)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+			continue
+		}
+		if want, have := test.json, string(b); want != have {
+			t.Errorf("\nexpected %s\ngot      %s", want, have)
+		}
+	}
+}
+
+func TestBeaufort_MarshalJSON(t *testing.T) {
+	b, err := json.Marshal(hkodata.Speed(30).Beaufort())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want, have := `{"Force":5,"Description":{"zh_HK":"清勁","en":"Fresh"}}`, string(b); want != have {
+		t.Errorf("\nexpected %s\ngot      %s", want, have)
+	}
+}
+
+func TestBeaufort_Description(t *testing.T) {
+	tests := []struct {
+		force    int
+		expected hkodata.I18nName
+	}{
+		{0, hkodata.I18nName{Zh: "無風", En: "Calm"}},
+		{4, hkodata.I18nName{Zh: "和緩", En: "Moderate"}},
+		{8, hkodata.I18nName{Zh: "烈風", En: "Gale"}},
+		{11, hkodata.I18nName{Zh: "暴風", En: "Storm"}},
+		{13, hkodata.I18nName{}},
+	}
+	for _, test := range tests {
+		if want, have := test.expected, hkodata.Beaufort(test.force).Description(); want != have {
+			t.Errorf("force %d: expected %#v, got %#v", test.force, want, have)
+		}
+	}
+}
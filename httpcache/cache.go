@@ -4,14 +4,13 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
-	"os"
+	"net/url"
+	"sync"
 	"time"
 
 	"github.com/yookoala/weatherhk/ctxlog"
-	rcache "gopkg.in/go-redis/cache.v5"
-	redis "gopkg.in/redis.v5"
+	"go4.org/syncutil/singleflight"
 )
 
 // Error represents error in httpcache
@@ -21,11 +20,17 @@ const (
 	// HeaderNotExists represents error if header field is empty
 	// or does not exists
 	HeaderNotExists Error = iota
+
+	// CacheMiss represents error if there is no cache of a key in Store
+	CacheMiss
 )
 
 func (err Error) Error() string {
-	if err == HeaderNotExists {
+	switch err {
+	case HeaderNotExists:
 		return "header field not exits"
+	case CacheMiss:
+		return "cache miss"
 	}
 	return "unknown error"
 }
@@ -53,32 +58,6 @@ func parseRFC2612(str string) (t time.Time, err error) {
 	return
 }
 
-var redisCache *rcache.Codec
-
-func init() {
-	redisURL, err := redis.ParseURL(os.Getenv("REDIS_URL"))
-	if err != nil {
-		log.Printf("invalid REDIS_URL: %s", err.Error())
-		return
-	}
-
-	// assign global redisCache
-	redisCache = &rcache.Codec{
-		Redis: redis.NewRing(&redis.RingOptions{
-			Addrs: map[string]string{
-				"default": redisURL.Addr,
-			},
-			Password: redisURL.Password,
-		}),
-		Marshal: func(v interface{}) ([]byte, error) {
-			return json.Marshal(v)
-		},
-		Unmarshal: func(b []byte, v interface{}) error {
-			return json.Unmarshal(b, v)
-		},
-	}
-}
-
 // NewCache wraps an http.ResponswWriter with Cache
 func NewCache(w http.ResponseWriter) *Cache {
 	return &Cache{
@@ -89,6 +68,21 @@ func NewCache(w http.ResponseWriter) *Cache {
 	}
 }
 
+// discardWriter is an http.ResponseWriter which only keeps the header
+type discardWriter struct {
+	header http.Header
+}
+
+func (w *discardWriter) Header() http.Header         { return w.header }
+func (w *discardWriter) Write(p []byte) (int, error) { return len(p), nil }
+func (w *discardWriter) WriteHeader(code int)        {}
+
+// newRecorder creates a Cache which records a response without writing
+// it to any client, so it can be written to multiple clients later
+func newRecorder() *Cache {
+	return NewCache(&discardWriter{header: make(http.Header)})
+}
+
 // Cache wraps an http.ResponseWriter and return
 type Cache struct {
 	responseWriter http.ResponseWriter
@@ -167,6 +161,46 @@ func (cache *Cache) WriteHeader(code int) {
 	cache.responseWriter.WriteHeader(code)
 }
 
+// queryParams are the query parameters responses vary by, or nil if
+// responses vary by all query parameters
+var queryParams map[string]bool
+var queryParamsMutex sync.RWMutex
+
+// SetQueryParams sets the query parameters responses vary by. Other
+// parameters are left out of the cache key, so made up parameters would
+// not bypass the cache. Responses vary by all parameters if none is set.
+func SetQueryParams(names ...string) {
+	queryParamsMutex.Lock()
+	defer queryParamsMutex.Unlock()
+	if len(names) == 0 {
+		queryParams = nil
+		return
+	}
+	queryParams = make(map[string]bool, len(names))
+	for _, name := range names {
+		queryParams[name] = true
+	}
+}
+
+// keyQuery returns the query parameters of the request which responses
+// vary by, encoded in the order of parameter names
+func keyQuery(r *http.Request) string {
+	queryParamsMutex.RLock()
+	defer queryParamsMutex.RUnlock()
+
+	query := r.URL.Query()
+	if queryParams == nil {
+		return query.Encode()
+	}
+	varied := make(url.Values, len(queryParams))
+	for name, values := range query {
+		if queryParams[name] {
+			varied[name] = values
+		}
+	}
+	return varied.Encode()
+}
+
 func keyOf(r *http.Request) (key string, err error) {
 	if r == nil {
 		err = fmt.Errorf("request cannot be nil")
@@ -177,22 +211,36 @@ func keyOf(r *http.Request) (key string, err error) {
 		return
 	}
 	key = "page:/" + r.URL.Path
+	if query := keyQuery(r); query != "" {
+		// response may vary by query parameters
+		key += "?" + query
+	}
 	return
 }
 
-// Load cache for a given http request
+// Load cache for a given http request. It returns nil cache without
+// error if there is no cache of the request.
 func Load(r *http.Request) (cache *Cache, err error) {
 	key, err := keyOf(r)
 	if err != nil {
 		return
 	}
 
-	if redisCache == nil {
+	s := currentStore()
+	if s == nil {
+		return
+	}
+
+	b, err := s.Get(key)
+	if err == CacheMiss {
+		err = nil
+		return
+	} else if err != nil {
 		return
 	}
 
 	cache = &Cache{}
-	if err = redisCache.Get(key, cache); err != nil {
+	if err = json.Unmarshal(b, cache); err != nil {
 		cache = nil
 		return
 	}
@@ -206,20 +254,41 @@ func Save(r *http.Request, cache *Cache) (err error) {
 		return
 	}
 
-	if redisCache == nil {
+	s := currentStore()
+	if s == nil {
 		return
 	}
 
 	// ensure that the header is cached
-	cache.CachedHeader = cache.Header()
-	cache.CachedContent = cache.String()
+	if cache.responseWriter != nil {
+		cache.CachedHeader = cache.Header()
+		cache.CachedContent = cache.String()
+	}
 
-	// store the httpcache item in memcached
-	return redisCache.Set(&rcache.Item{
-		Key:        key,
-		Object:     cache,
-		Expiration: 60 * time.Minute, // TODO: detect correct expiration time
-	})
+	// skip responses not to be stored in shared cache, or would not be
+	// served anyway
+	if !ParseCacheControl(cache.Header()).storable() || requestCacheControl(r).Has("no-store") {
+		return
+	}
+	expires, ok := freshUntil(cache)
+	if !ok {
+		return
+	}
+	if stale := staleUntil(cache); stale.After(expires) {
+		expires = stale
+	}
+	expiration := time.Until(expires)
+	if expiration <= 0 {
+		return
+	}
+
+	// store the httpcache item in the store, as long as it could be
+	// served fresh or stale
+	b, err := json.Marshal(cache)
+	if err != nil {
+		return
+	}
+	return s.Set(key, b, expiration)
 }
 
 // Delete deletes cache of a given request
@@ -229,38 +298,55 @@ func Delete(r *http.Request) (err error) {
 		return
 	}
 
-	if redisCache == nil {
+	s := currentStore()
+	if s == nil {
 		return
 	}
 
-	return redisCache.Delete(key)
+	return s.Delete(key)
 }
 
-// Valid test if a cache has valid cache
+// Valid test if a cache is fresh enough to serve the request, by the
+// Cache-Control directives of both the cached response and the request
 func Valid(r *http.Request, cache *Cache) bool {
-	var expires time.Time
-	var err error
+	if cache == nil {
+		return false
+	}
+	infoLog, _ := ctxlog.GetLoggers(r)
+
+	// client requires a fresh or young enough response
+	reqCC := requestCacheControl(r)
+	if reqCC.Has("no-cache") {
+		infoLog.Log("message", "cache bypassed by request")
+		return false
+	}
+	if maxAge, ok := reqCC.Seconds("max-age"); ok && time.Since(cache.Created) > maxAge {
+		infoLog.Log("message", "cache older than max-age of request")
+		return false
+	}
+	return fresh(r, cache)
+}
+
+// fresh tells if the cache is fresh by the response headers only,
+// regardless of the cache directives of the request
+func fresh(r *http.Request, cache *Cache) bool {
 	infoLog, errorLog := ctxlog.GetLoggers(r)
 
-	// TODO: might support max-age somehow?
+	// response requires revalidation on every use, which is not supported
+	cc := ParseCacheControl(cache.Header())
+	if cc.Has("no-cache") || cc.Has("no-store") || cc.Has("private") {
+		return false
+	}
 
-	// parse grace expires override
-	if expires, err = cache.ParseTime("X-Grace-Expires"); err == nil {
-		if expires.After(time.Now()) {
-			infoLog.Log("message", "cache graced")
-			return true
+	for _, name := range []string{"Expires", "X-Grace-Expires"} {
+		if _, err := cache.ParseTime(name); err != nil && err != HeaderNotExists {
+			errorLog.Log("message", fmt.Sprintf("error parsing %s (%s)", name, err.Error()))
 		}
-	} else if err != HeaderNotExists {
-		errorLog.Log("message", fmt.Sprintf("error parsing X-Grace-Expires (%s)", err.Error()))
 	}
 
-	if expires, err = cache.ParseTime("Expires"); err == nil {
-		if expires.After(time.Now()) {
-			infoLog.Log("message", "cache not expired")
-			return true
-		}
-	} else if err != HeaderNotExists {
-		errorLog.Log("message", fmt.Sprintf("error parsing Expires (%s)", err.Error()))
+	if expires, ok := freshUntil(cache); ok && expires.After(time.Now()) {
+		infoLog.Log("message", "cache not expired")
+		return true
 	}
 	return false // default treat as expired
 }
@@ -284,16 +370,128 @@ func CacheHandler(inner http.Handler) http.Handler {
 			return // early return
 		}
 
-		// refresh cache by running inner handler
-		infoLog.Log("message", "no valid cache, trigger inner handler")
+		// refresh cache by running inner handler, once for all
+		// concurrent requests of the same key
+		key, err := keyOf(r)
+		if err != nil {
+			errorLog.Log("message", fmt.Sprintf("error refreshing cache: %s", err.Error()))
+			inner.ServeHTTP(w, r)
+			return
+		}
+		doRefresh := func() (interface{}, error) {
+			return refresh(inner, r), nil
+		}
+
+		// serve stale cache while refreshing in background (RFC 5861),
+		// unless client requires a fresh or young enough response
+		reqCC := requestCacheControl(r)
+		if !reqCC.Has("no-cache") && !reqCC.Has("max-age") && canServeStale(cache, "stale-while-revalidate") {
+			infoLog.Log("message", "use stale cache, refresh in background")
+			go refreshGroup.Do(key, doRefresh)
+			w.Header().Set("Warning", `110 - "Response is Stale"`)
+			cache.WriteTo(w)
+			return
+		}
+
+		refreshed, _ := refreshGroup.Do(key, doRefresh)
+
+		// serve stale cache if failed to refresh (RFC 5861)
+		if refreshed.(*Cache).Code() >= 500 && canServeStale(cache, "stale-if-error") {
+			errorLog.Log("message", fmt.Sprintf("refresh failed with status %d, use stale cache", refreshed.(*Cache).Code()))
+			w.Header().Set("Warning", `111 - "Revalidation Failed"`)
+			cache.WriteTo(w)
+			return
+		}
+		refreshed.(*Cache).WriteTo(w)
+	})
+}
+
+// refreshGroup coalesces concurrent refreshes of the same key
+var refreshGroup singleflight.Group
 
-		cache = NewCache(w)
-		inner.ServeHTTP(cache, r)
-		go func() {
-			err := Save(r, cache)
-			if err != nil {
+// refresh runs the inner handler to refresh the cache of the request and
+// saves it, unless it failed (i.e. status 5xx), so the stale cache is kept
+// for stale-if-error. If a Locker is set, only the instance acquiring the
+// lock runs the inner handler and the others wait for its cache.
+func refresh(inner http.Handler, r *http.Request) *Cache {
+	infoLog, errorLog := ctxlog.GetLoggers(r)
+
+	var unlock func() error
+	if l := currentLocker(); l != nil {
+		key, _ := keyOf(r)
+		var err error
+		if unlock, err = l.TryLock(key, lockTTL); err != nil {
+			errorLog.Log("message", fmt.Sprintf("error acquiring lock: %s", err.Error()))
+		} else if unlock == nil {
+			infoLog.Log("message", "cache refreshing by another instance, wait for it")
+			if cache := waitForCache(r); cache != nil {
+				return cache
+			}
+		}
+	}
+
+	infoLog.Log("message", "no valid cache, trigger inner handler")
+	cache := serveRecorded(inner, r)
+
+	go func() {
+		if cache.Code() < 500 {
+			if err := Save(r, cache); err != nil {
 				errorLog.Log("message", fmt.Sprintf("error saving cache: %s", err.Error()))
 			}
-		}()
-	})
+		}
+		if unlock != nil {
+			if err := unlock(); err != nil {
+				errorLog.Log("message", fmt.Sprintf("error releasing lock: %s", err.Error()))
+			}
+		}
+	}()
+	return cache
+}
+
+// serveRecorded runs the inner handler and returns the recorded response.
+// Panic of the inner handler is recorded as internal server error.
+func serveRecorded(inner http.Handler, r *http.Request) (cache *Cache) {
+	defer func() {
+		if err := recover(); err != nil {
+			_, errorLog := ctxlog.GetLoggers(r)
+			errorLog.Log("message", fmt.Sprintf("panic in inner handler: %v", err))
+			cache = newRecorder()
+			cache.WriteHeader(http.StatusInternalServerError)
+			cache.freeze()
+		}
+	}()
+	cache = newRecorder()
+	inner.ServeHTTP(cache, r)
+	cache.freeze()
+	return
+}
+
+// freeze stops recording and keeps the recorded response for writing to
+// all waiting clients
+func (cache *Cache) freeze() {
+	cache.CachedHeader = cache.Header()
+	cache.CachedContent = cache.String()
+	cache.responseWriter = nil
+}
+
+// waitForCache waits for valid cache of the request to be saved by
+// another instance. It returns nil if none is saved in time.
+func waitForCache(r *http.Request) *Cache {
+	// the cache refreshed by the lock holder is newer than the request, so
+	// it satisfies requests bypassing cache (e.g. "no-cache" on reload)
+	var previous time.Time
+	if cache, err := Load(r); err == nil && cache != nil {
+		previous = cache.Created
+	}
+	for deadline := time.Now().Add(lockWait); time.Now().Before(deadline); {
+		time.Sleep(lockPoll)
+		cache, err := Load(r)
+		if err != nil || cache == nil {
+			continue
+		}
+		if Valid(r, cache) || (cache.Created.After(previous) && fresh(r, cache)) {
+			return cache
+		}
+	}
+	return nil
 }
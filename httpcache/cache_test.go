@@ -129,10 +129,6 @@ func TestCache_WriteTo(t *testing.T) {
 }
 
 func TestLoad(t *testing.T) {
-	if url := os.Getenv("REDIS_URL"); url == "" {
-		t.Skip("REDIS_URL not set, test skipped")
-	}
-
 	_, err := httpcache.Load(nil)
 	if err == nil {
 		t.Errorf("expected error, got nil")
@@ -146,12 +142,52 @@ func TestLoad(t *testing.T) {
 	}
 }
 
-func TestCacheHandler(t *testing.T) {
+func TestLoad_query(t *testing.T) {
+	save := func(target string) {
+		r, _ := http.NewRequest("GET", target, nil)
+		cache := httpcache.NewCache(httptest.NewRecorder())
+		cache.Header().Set("Expires", rfc2616(time.Now().Add(time.Minute)))
+		fmt.Fprint(cache, target)
+		if err := httpcache.Save(r, cache); err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+	}
+	load := func(target string) string {
+		r, _ := http.NewRequest("GET", target, nil)
+		cache, err := httpcache.Load(r)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+		if cache == nil {
+			return ""
+		}
+		return cache.String()
+	}
+	defer func() {
+		r, _ := http.NewRequest("GET", "/query.html?lat=1&lon=2", nil)
+		httpcache.Delete(r)
+	}()
 
-	if url := os.Getenv("REDIS_URL"); url == "" {
-		t.Skip("REDIS_URL not set, test skipped")
+	save("/query.html?lat=1&lon=2")
+
+	// query parameters in any order
+	if want, have := "/query.html?lat=1&lon=2", load("/query.html?lon=2&lat=1"); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
 	}
 
+	// made up query parameters are ignored
+	httpcache.SetQueryParams("lat", "lon")
+	defer httpcache.SetQueryParams()
+	if want, have := "/query.html?lat=1&lon=2", load("/query.html?lat=1&lon=2&x=1"); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := "", load("/query.html?lat=1&lon=3"); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
+
+func TestCacheHandler(t *testing.T) {
+
 	// handler to be wrapped
 	calledHandler := 0
 	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,94 @@
+package httpcache
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CacheControl contains the directives in Cache-Control header
+// (RFC 7234), keyed by lower case directive name. Directives without
+// argument (e.g. "no-store") have empty value.
+type CacheControl map[string]string
+
+// ParseCacheControl parses the Cache-Control fields of the header
+func ParseCacheControl(header http.Header) CacheControl {
+	cc := make(CacheControl)
+	for _, value := range header["Cache-Control"] {
+		for _, part := range strings.Split(value, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			name, arg := part, ""
+			if i := strings.Index(part, "="); i >= 0 {
+				name, arg = part[:i], strings.Trim(strings.TrimSpace(part[i+1:]), `"`)
+			}
+			name = strings.ToLower(strings.TrimSpace(name))
+			if _, exists := cc[name]; !exists {
+				// the first occurrence takes precedence
+				cc[name] = arg
+			}
+		}
+	}
+	return cc
+}
+
+// requestCacheControl parses the cache directives of a request. Pragma
+// "no-cache" is honoured for HTTP/1.0 clients without Cache-Control.
+func requestCacheControl(r *http.Request) CacheControl {
+	cc := ParseCacheControl(r.Header)
+	if _, ok := r.Header["Cache-Control"]; !ok {
+		for _, pragma := range r.Header["Pragma"] {
+			if strings.Contains(strings.ToLower(pragma), "no-cache") {
+				cc["no-cache"] = ""
+			}
+		}
+	}
+	return cc
+}
+
+// Has reports if the directive is present
+func (cc CacheControl) Has(directive string) bool {
+	_, ok := cc[directive]
+	return ok
+}
+
+// Seconds returns the delta seconds argument of the directive (e.g.
+// "max-age=60"). It is not ok if the directive is absent or invalid.
+func (cc CacheControl) Seconds(directive string) (seconds time.Duration, ok bool) {
+	arg, ok := cc[directive]
+	if !ok {
+		return 0, false
+	}
+	delta, err := strconv.ParseUint(arg, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(delta) * time.Second, true
+}
+
+// storable reports if a shared cache can store the response
+func (cc CacheControl) storable() bool {
+	return !cc.Has("no-store") && !cc.Has("private")
+}
+
+// freshUntil returns the time the cache stops being fresh, by the
+// s-maxage or max-age directive, or the Expires header, in the order of
+// precedence. It is extended by the X-Grace-Expires header if later. It
+// is not ok if the response has no expiration information.
+func freshUntil(cache *Cache) (expires time.Time, ok bool) {
+	cc := ParseCacheControl(cache.Header())
+	if maxAge, found := cc.Seconds("s-maxage"); found {
+		expires, ok = cache.Created.Add(maxAge), true
+	} else if maxAge, found := cc.Seconds("max-age"); found {
+		expires, ok = cache.Created.Add(maxAge), true
+	} else if t, err := cache.ParseTime("Expires"); err == nil {
+		expires, ok = t, true
+	}
+	if t, err := cache.ParseTime("X-Grace-Expires"); err == nil && t.After(expires) {
+		expires, ok = t, true
+	}
+	return
+}
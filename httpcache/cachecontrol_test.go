@@ -0,0 +1,196 @@
+package httpcache_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yookoala/weatherhk/httpcache"
+)
+
+func TestParseCacheControl(t *testing.T) {
+	header := http.Header{}
+	header.Add("Cache-Control", `public, max-age=60, S-MaxAge="120"`)
+	header.Add("Cache-Control", "no-store,, max-age=30")
+
+	cc := httpcache.ParseCacheControl(header)
+	expected := httpcache.CacheControl{
+		"public":   "",
+		"max-age":  "60",
+		"s-maxage": "120",
+		"no-store": "",
+	}
+	if want, have := expected, cc; !reflect.DeepEqual(want, have) {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if seconds, ok := cc.Seconds("s-maxage"); !ok || seconds != 120*time.Second {
+		t.Errorf("expected 120s, got %s (ok: %#v)", seconds, ok)
+	}
+	if _, ok := cc.Seconds("public"); ok {
+		t.Errorf("expected not ok for directive without delta seconds")
+	}
+	if !cc.Has("no-store") {
+		t.Errorf("expected no-store directive")
+	}
+}
+
+func TestValid_cacheControl(t *testing.T) {
+	tests := []struct {
+		desc          string
+		expires       time.Duration
+		cacheControl  string
+		reqHeader     http.Header
+		expectedValid bool
+	}{
+		{
+			desc:          "max-age over Expires",
+			expires:       -time.Minute,
+			cacheControl:  "public, max-age=60",
+			expectedValid: true,
+		},
+		{
+			desc:          "s-maxage over max-age",
+			expires:       time.Minute,
+			cacheControl:  "public, max-age=60, s-maxage=0",
+			expectedValid: false,
+		},
+		{
+			desc:          "no-cache response",
+			expires:       time.Minute,
+			cacheControl:  "no-cache",
+			expectedValid: false,
+		},
+		{
+			desc:          "private response",
+			expires:       time.Minute,
+			cacheControl:  "private, max-age=60",
+			expectedValid: false,
+		},
+		{
+			desc:          "no-cache request",
+			expires:       time.Minute,
+			reqHeader:     http.Header{"Cache-Control": {"no-cache"}},
+			expectedValid: false,
+		},
+		{
+			desc:          "max-age request",
+			expires:       time.Minute,
+			reqHeader:     http.Header{"Cache-Control": {"max-age=0"}},
+			expectedValid: false,
+		},
+		{
+			desc:          "max-age request of young cache",
+			expires:       time.Minute,
+			reqHeader:     http.Header{"Cache-Control": {"max-age=60"}},
+			expectedValid: true,
+		},
+		{
+			desc:          "Pragma no-cache request",
+			expires:       time.Minute,
+			reqHeader:     http.Header{"Pragma": {"no-cache"}},
+			expectedValid: false,
+		},
+		{
+			desc:          "Pragma ignored with Cache-Control",
+			expires:       time.Minute,
+			reqHeader:     http.Header{"Pragma": {"no-cache"}, "Cache-Control": {"max-age=60"}},
+			expectedValid: true,
+		},
+	}
+
+	for _, test := range tests {
+		cache := httpcache.NewCache(httptest.NewRecorder())
+		cache.Header().Set("Expires", rfc2616(time.Now().Add(test.expires)))
+		if test.cacheControl != "" {
+			cache.Header().Set("Cache-Control", test.cacheControl)
+		}
+		r, _ := http.NewRequest("GET", "/dummy.html", nil)
+		for name, values := range test.reqHeader {
+			r.Header[name] = values
+		}
+		if want, have := test.expectedValid, httpcache.Valid(r, cache); want != have {
+			t.Errorf("%s: expected %#v, got %#v", test.desc, want, have)
+		}
+	}
+}
+
+// expirationStore records the expiration of entries set
+type expirationStore struct {
+	*httpcache.MemoryStore
+	expirations map[string]time.Duration
+}
+
+func (s *expirationStore) Set(key string, value []byte, expiration time.Duration) error {
+	s.expirations[key] = expiration
+	return s.MemoryStore.Set(key, value, expiration)
+}
+
+func TestSave_expiration(t *testing.T) {
+	store := &expirationStore{
+		MemoryStore: httpcache.NewMemoryStore(10),
+		expirations: make(map[string]time.Duration),
+	}
+	httpcache.SetStore(store)
+	defer httpcache.SetStore(httpcache.NewMemoryStore(100))
+
+	tests := []struct {
+		path         string
+		cacheControl string
+		reqHeader    http.Header
+		stored       bool
+		expiration   time.Duration
+	}{
+		{"/expires.html", "", nil, true, 10 * time.Minute},
+		{"/max-age.html", "max-age=60", nil, true, time.Minute},
+		{"/s-maxage.html", "max-age=60, s-maxage=120", nil, true, 2 * time.Minute},
+		{"/stale.html", "max-age=60, stale-if-error=3600", nil, true, 61 * time.Minute},
+		{"/must-revalidate.html", "max-age=60, stale-if-error=3600, must-revalidate", nil, true, time.Minute},
+		{"/no-store.html", "no-store", nil, false, 0},
+		{"/private.html", "private, max-age=60", nil, false, 0},
+		{"/request-no-store.html", "", http.Header{"Cache-Control": {"no-store"}}, false, 0},
+	}
+	for _, test := range tests {
+		cache := httpcache.NewCache(httptest.NewRecorder())
+		cache.Header().Set("Expires", rfc2616(time.Now().Add(10*time.Minute)))
+		if test.cacheControl != "" {
+			cache.Header().Set("Cache-Control", test.cacheControl)
+		}
+		fmt.Fprint(cache, "message")
+		r, _ := http.NewRequest("GET", test.path, nil)
+		for name, values := range test.reqHeader {
+			r.Header[name] = values
+		}
+		if err := httpcache.Save(r, cache); err != nil {
+			t.Errorf("%s: unexpected error: %s", test.path, err)
+			continue
+		}
+
+		expiration, stored := store.expirations["page:/"+test.path]
+		if want, have := test.stored, stored; want != have {
+			t.Errorf("%s: expected stored to be %#v, got %#v", test.path, want, have)
+			continue
+		}
+		if diff := test.expiration - expiration; diff < 0 || diff > 2*time.Second {
+			t.Errorf("%s: expected expiration %s, got %s", test.path, test.expiration, expiration)
+		}
+	}
+}
+
+func TestCacheHandler_mustRevalidate(t *testing.T) {
+	handler := httpcache.CacheHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprint(w, "Error message")
+	}))
+	r, _ := http.NewRequest("GET", "/must-revalidate.html", nil)
+	defer httpcache.Delete(r)
+
+	saveExpired(t, r, "max-age=0, stale-if-error=60, must-revalidate")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if want, have := http.StatusBadGateway, w.Code; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
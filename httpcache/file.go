@@ -0,0 +1,165 @@
+package httpcache
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileStore is a Store which keeps entries as files in a directory, so
+// cache survives restart of the process. Expired entries are removed when
+// read, and by a sweep of the directory started by Set every
+// FileSweepInterval, so entries never read again do not pile up.
+type FileStore struct {
+	dir       string
+	mutex     sync.Mutex
+	lastSweep time.Time
+}
+
+// FileSweepInterval is the minimum interval between sweeps of expired
+// entries started by FileStore.Set
+var FileSweepInterval = 10 * time.Minute
+
+// fileTmpPrefix is the prefix of temporary files written by Set
+const fileTmpPrefix = ".tmp-"
+
+type fileEntry struct {
+	Key     string
+	Expires time.Time
+	Value   []byte
+}
+
+// NewFileStore creates a FileStore in the directory, which is created
+// if not exists
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("unable to create cache directory: %s", err.Error())
+	}
+	return &FileStore{dir: dir, lastSweep: time.Now()}, nil
+}
+
+// pathOf returns the path of file to store the key. Keys are hashed as
+// they might contain characters not allowed in file names.
+func (s *FileStore) pathOf(key string) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%x.json", sha1.Sum([]byte(key))))
+}
+
+// Get implements Store
+func (s *FileStore) Get(key string) ([]byte, error) {
+	path := s.pathOf(key)
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, CacheMiss
+	} else if err != nil {
+		return nil, err
+	}
+
+	var entry fileEntry
+	if err := json.Unmarshal(b, &entry); err != nil {
+		return nil, fmt.Errorf("unable to decode cache file %s: %s", path, err.Error())
+	}
+	if entry.Key != key {
+		return nil, CacheMiss // hash collision
+	}
+	if !entry.Expires.After(time.Now()) {
+		os.Remove(path)
+		return nil, CacheMiss
+	}
+	return entry.Value, nil
+}
+
+// Set implements Store
+func (s *FileStore) Set(key string, value []byte, expiration time.Duration) error {
+	b, err := json.Marshal(fileEntry{
+		Key:     key,
+		Expires: time.Now().Add(expiration),
+		Value:   value,
+	})
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file then rename, so readers would never
+	// see a partially written file
+	tmp, err := ioutil.TempFile(s.dir, fileTmpPrefix)
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err = os.Rename(tmp.Name(), s.pathOf(key)); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	s.mutex.Lock()
+	if time.Since(s.lastSweep) >= FileSweepInterval {
+		s.lastSweep = time.Now()
+		go s.Sweep()
+	}
+	s.mutex.Unlock()
+	return nil
+}
+
+// Sweep removes the files of expired entries, and temporary files left
+// by interrupted Set, from the directory. Files unreadable or not
+// written by FileStore are left untouched.
+func (s *FileStore) Sweep() error {
+	infos, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+		path := filepath.Join(s.dir, info.Name())
+
+		// temporary files are renamed right after written
+		if strings.HasPrefix(info.Name(), fileTmpPrefix) {
+			if now.Sub(info.ModTime()) > FileSweepInterval {
+				os.Remove(path)
+			}
+			continue
+		}
+
+		if filepath.Ext(info.Name()) != ".json" {
+			continue
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var entry fileEntry
+		if err := json.Unmarshal(b, &entry); err != nil {
+			continue
+		}
+		if !entry.Expires.After(now) {
+			os.Remove(path)
+		}
+	}
+	return nil
+}
+
+// Delete implements Store
+func (s *FileStore) Delete(key string) error {
+	err := os.Remove(s.pathOf(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
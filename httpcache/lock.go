@@ -0,0 +1,97 @@
+package httpcache
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	redis "gopkg.in/redis.v5"
+)
+
+// Locker provides locks shared among instances of the application, so
+// only one of them refreshes an expired cache at a time
+type Locker interface {
+
+	// TryLock tries to acquire the lock of the key for the ttl without
+	// waiting. It returns the function to release the lock if acquired,
+	// or nil if the lock is held by others.
+	TryLock(key string, ttl time.Duration) (unlock func() error, err error)
+}
+
+// lockTTL is the longest time to hold a lock, in case the holder never
+// releases it (e.g. crashed)
+const lockTTL = 10 * time.Second
+
+// lockWait is the longest time to wait for the cache refreshed by the
+// lock holder, before refreshing it anyway
+const lockWait = 5 * time.Second
+
+// lockPoll is the interval to check for the cache refreshed by the lock
+// holder
+const lockPoll = 50 * time.Millisecond
+
+var locker Locker
+var lockerMutex sync.RWMutex
+
+// SetLocker sets the Locker for refreshing cache among instances. Only
+// concurrent requests in the same instance are coalesced if it is nil.
+func SetLocker(l Locker) {
+	lockerMutex.Lock()
+	defer lockerMutex.Unlock()
+	locker = l
+}
+
+// currentLocker returns the Locker for refreshing cache, or nil
+func currentLocker() Locker {
+	lockerMutex.RLock()
+	defer lockerMutex.RUnlock()
+	return locker
+}
+
+// lockerOf returns the Locker suitable for the Store, or nil if the
+// store is not shared among instances
+func lockerOf(s Store) Locker {
+	switch s := s.(type) {
+	case *RedisStore:
+		return NewRedisLocker(s)
+	case *TieredStore:
+		return lockerOf(s.far)
+	}
+	return nil
+}
+
+// RedisLocker is a Locker with locks in redis server
+type RedisLocker struct {
+	client *redis.Client
+}
+
+// NewRedisLocker creates a RedisLocker on the redis server of the store
+func NewRedisLocker(s *RedisStore) *RedisLocker {
+	return &RedisLocker{client: s.client}
+}
+
+// unlockScript deletes the lock only if it is still held by the token,
+// so a lock expired and acquired by others would not be released
+const unlockScript = `if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+end
+return 0`
+
+// TryLock implements Locker
+func (l *RedisLocker) TryLock(key string, ttl time.Duration) (unlock func() error, err error) {
+	var token [16]byte
+	if _, err = rand.Read(token[:]); err != nil {
+		return
+	}
+	lockKey, tokenStr := "lock:"+key, hex.EncodeToString(token[:])
+
+	acquired, err := l.client.SetNX(lockKey, tokenStr, ttl).Result()
+	if err != nil || !acquired {
+		return
+	}
+	unlock = func() error {
+		return l.client.Eval(unlockScript, []string{lockKey}, tokenStr).Err()
+	}
+	return
+}
@@ -0,0 +1,133 @@
+package httpcache_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yookoala/weatherhk/httpcache"
+)
+
+func TestCacheHandler_coalesce(t *testing.T) {
+	var called int32
+	handler := httpcache.CacheHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&called, 1)
+		time.Sleep(50 * time.Millisecond)
+		w.Header().Add("Expires", rfc2616(time.Now().Add(60*time.Second)))
+		w.WriteHeader(http.StatusPartialContent)
+		fmt.Fprint(w, "Coalesced message")
+	}))
+	r, _ := http.NewRequest("GET", "/coalesce.html", nil)
+	defer httpcache.Delete(r)
+
+	var wg sync.WaitGroup
+	recorders := make([]*httptest.ResponseRecorder, 10)
+	for i := range recorders {
+		recorders[i] = httptest.NewRecorder()
+		wg.Add(1)
+		go func(w *httptest.ResponseRecorder) {
+			defer wg.Done()
+			handler.ServeHTTP(w, r)
+		}(recorders[i])
+	}
+	wg.Wait()
+
+	if want, have := int32(1), atomic.LoadInt32(&called); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	for i, w := range recorders {
+		if want, have := http.StatusPartialContent, w.Code; want != have {
+			t.Errorf("recorders[%d]: expected %#v, got %#v", i, want, have)
+		}
+		if want, have := "Coalesced message", w.Body.String(); want != have {
+			t.Errorf("recorders[%d]: expected %#v, got %#v", i, want, have)
+		}
+		if w.Header().Get("Expires") == "" {
+			t.Errorf("recorders[%d]: expected Expires header, got none", i)
+		}
+	}
+}
+
+// heldLocker is a Locker of which all locks are held by another instance
+type heldLocker struct{}
+
+func (heldLocker) TryLock(key string, ttl time.Duration) (func() error, error) {
+	return nil, nil
+}
+
+func TestCacheHandler_locked(t *testing.T) {
+	httpcache.SetLocker(heldLocker{})
+	defer httpcache.SetLocker(nil)
+
+	var called int32
+	handler := httpcache.CacheHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&called, 1)
+		fmt.Fprint(w, "Message of this instance")
+	}))
+	r, _ := http.NewRequest("GET", "/locked.html", nil)
+	defer httpcache.Delete(r)
+
+	// another instance holding the lock saves its cache a bit later
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		cache := httpcache.NewCache(httptest.NewRecorder())
+		cache.Header().Add("Expires", rfc2616(time.Now().Add(60*time.Second)))
+		fmt.Fprint(cache, "Message of another instance")
+		httpcache.Save(r, cache)
+	}()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if want, have := int32(0), atomic.LoadInt32(&called); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := "Message of another instance", w.Body.String(); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
+
+func TestCacheHandler_lockedNoCache(t *testing.T) {
+	httpcache.SetLocker(heldLocker{})
+	defer httpcache.SetLocker(nil)
+
+	var called int32
+	handler := httpcache.CacheHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&called, 1)
+		fmt.Fprint(w, "Message of this instance")
+	}))
+	saved, _ := http.NewRequest("GET", "/locked-no-cache.html", nil)
+	defer httpcache.Delete(saved)
+	save := func(body string) {
+		cache := httpcache.NewCache(httptest.NewRecorder())
+		cache.Header().Add("Expires", rfc2616(time.Now().Add(60*time.Second)))
+		fmt.Fprint(cache, body)
+		httpcache.Save(saved, cache)
+	}
+	save("Old message of another instance")
+
+	// another instance holding the lock saves its cache a bit later
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		save("Message of another instance")
+	}()
+
+	// browser reload bypasses the old cache, but not the refreshed one
+	r, _ := http.NewRequest("GET", "/locked-no-cache.html", nil)
+	r.Header.Set("Cache-Control", "no-cache")
+	w := httptest.NewRecorder()
+	start := time.Now()
+	handler.ServeHTTP(w, r)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected not to wait for the lock, took %s", elapsed)
+	}
+	if want, have := int32(0), atomic.LoadInt32(&called); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := "Message of another instance", w.Body.String(); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
@@ -0,0 +1,111 @@
+package httpcache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-process Store which keeps up to a maximum number
+// of entries, evicting the least recently used
+type MemoryStore struct {
+	mutex      sync.Mutex
+	maxEntries int
+	list       *list.List // of *memoryEntry, most recently used first
+	table      map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryStore creates a MemoryStore of maximum number of entries
+func NewMemoryStore(maxEntries int) *MemoryStore {
+	return &MemoryStore{
+		maxEntries: maxEntries,
+		list:       list.New(),
+		table:      make(map[string]*list.Element),
+	}
+}
+
+// Get implements Store
+func (s *MemoryStore) Get(key string) ([]byte, error) {
+	b, _, err := s.GetTTL(key)
+	return b, err
+}
+
+// GetTTL returns the value stored with the key and its remaining time
+// to live
+func (s *MemoryStore) GetTTL(key string) ([]byte, time.Duration, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	el, ok := s.table[key]
+	if !ok {
+		return nil, 0, CacheMiss
+	}
+	entry := el.Value.(*memoryEntry)
+	ttl := time.Until(entry.expires)
+	if ttl <= 0 {
+		s.remove(el)
+		return nil, 0, CacheMiss
+	}
+	s.list.MoveToFront(el)
+	return entry.value, ttl, nil
+}
+
+// Set implements Store
+func (s *MemoryStore) Set(key string, value []byte, expiration time.Duration) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// copy the value so later changes of caller would not affect it
+	entry := &memoryEntry{
+		key:     key,
+		value:   append([]byte(nil), value...),
+		expires: time.Now().Add(expiration),
+	}
+	if el, ok := s.table[key]; ok {
+		el.Value = entry
+		s.list.MoveToFront(el)
+		return nil
+	}
+	s.table[key] = s.list.PushFront(entry)
+	for s.maxEntries > 0 && s.list.Len() > s.maxEntries {
+		s.remove(s.list.Back())
+	}
+	return nil
+}
+
+// Delete implements Store
+func (s *MemoryStore) Delete(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if el, ok := s.table[key]; ok {
+		s.remove(el)
+	}
+	return nil
+}
+
+// Len returns the number of entries in the store, including expired
+// entries not yet removed
+func (s *MemoryStore) Len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.list.Len()
+}
+
+// purge removes all entries
+func (s *MemoryStore) purge() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.list.Init()
+	s.table = make(map[string]*list.Element)
+}
+
+func (s *MemoryStore) remove(el *list.Element) {
+	s.list.Remove(el)
+	delete(s.table, el.Value.(*memoryEntry).key)
+}
@@ -0,0 +1,136 @@
+package httpcache
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	redis "gopkg.in/redis.v5"
+)
+
+// RedisStore is a Store backed by redis server, which can be shared by
+// multiple instances of the application
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore creates a RedisStore of the redis server URL
+// (e.g. "redis://:password@localhost:6379")
+func NewRedisStore(redisURL string) (*RedisStore, error) {
+	options, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid REDIS_URL: %s", err.Error())
+	}
+	return &RedisStore{
+		client: redis.NewClient(options),
+	}, nil
+}
+
+// Get implements Store
+func (s *RedisStore) Get(key string) ([]byte, error) {
+	b, err := s.client.Get(key).Bytes()
+	if err == redis.Nil {
+		return nil, CacheMiss
+	}
+	return b, err
+}
+
+// Set implements Store
+func (s *RedisStore) Set(key string, value []byte, expiration time.Duration) error {
+	return s.client.Set(key, value, expiration).Err()
+}
+
+// Delete implements Store
+func (s *RedisStore) Delete(key string) error {
+	return s.client.Del(key).Err()
+}
+
+// GetTTL returns the value stored with the key and its remaining time
+// to live
+func (s *RedisStore) GetTTL(key string) (b []byte, ttl time.Duration, err error) {
+	var get *redis.StringCmd
+	var pttl *redis.DurationCmd
+	s.client.Pipelined(func(pipe *redis.Pipeline) error {
+		get = pipe.Get(key)
+		pttl = pipe.PTTL(key)
+		return nil
+	})
+	if b, err = get.Bytes(); err == redis.Nil {
+		return nil, 0, CacheMiss
+	} else if err != nil {
+		return
+	}
+	ttl, err = pttl.Result()
+	return
+}
+
+// RedisInvalidator is an Invalidator which broadcasts invalidations
+// through redis pub/sub
+type RedisInvalidator struct {
+	client  *redis.Client
+	channel string
+	id      string // identifies the instance to ignore own messages
+
+	mutex  sync.Mutex
+	pubsub *redis.PubSub
+	closed bool
+}
+
+// NewRedisInvalidator creates a RedisInvalidator which broadcasts on the
+// channel of the redis server of the store
+func NewRedisInvalidator(s *RedisStore, channel string) *RedisInvalidator {
+	var id [8]byte
+	rand.Read(id[:])
+	return &RedisInvalidator{
+		client:  s.client,
+		channel: channel,
+		id:      hex.EncodeToString(id[:]),
+	}
+}
+
+// Invalidate implements Invalidator
+func (inv *RedisInvalidator) Invalidate(key string) error {
+	return inv.client.Publish(inv.channel, inv.id+" "+key).Err()
+}
+
+// Listen implements Invalidator
+func (inv *RedisInvalidator) Listen(ready func(), fn func(key string)) error {
+	pubsub, err := inv.client.Subscribe(inv.channel)
+	if err != nil {
+		return err
+	}
+	inv.mutex.Lock()
+	if inv.closed {
+		inv.mutex.Unlock()
+		return pubsub.Close()
+	}
+	inv.pubsub = pubsub
+	inv.mutex.Unlock()
+	ready()
+
+	for {
+		msg, err := pubsub.ReceiveMessage()
+		if err != nil {
+			return err
+		}
+		parts := strings.SplitN(msg.Payload, " ", 2)
+		if len(parts) != 2 || parts[0] == inv.id {
+			continue
+		}
+		fn(parts[1])
+	}
+}
+
+// Close implements Invalidator
+func (inv *RedisInvalidator) Close() error {
+	inv.mutex.Lock()
+	defer inv.mutex.Unlock()
+	inv.closed = true
+	if inv.pubsub == nil {
+		return nil
+	}
+	return inv.pubsub.Close()
+}
@@ -0,0 +1,77 @@
+package httpcache
+
+import (
+	"sync"
+	"time"
+)
+
+// default windows to serve stale cache, for responses without the
+// stale-while-revalidate or stale-if-error directives (RFC 5861)
+var staleWhileRevalidate, staleIfError time.Duration
+var staleMutex sync.RWMutex
+
+// SetStaleWindows sets the default windows, after a cache expired, to
+// serve it while refreshing in background (stale-while-revalidate) and
+// to serve it if refreshing failed (stale-if-error). They apply to
+// responses without the directives in Cache-Control header.
+func SetStaleWindows(whileRevalidate, ifError time.Duration) {
+	staleMutex.Lock()
+	defer staleMutex.Unlock()
+	staleWhileRevalidate, staleIfError = whileRevalidate, ifError
+}
+
+// staleWindows returns the windows to serve the stale cache. They are
+// zero if the response forbids serving stale (e.g. must-revalidate).
+func staleWindows(cache *Cache) (whileRevalidate, ifError time.Duration) {
+	cc := ParseCacheControl(cache.Header())
+	if cc.Has("must-revalidate") || cc.Has("proxy-revalidate") || cc.Has("no-cache") || cc.Has("no-store") {
+		return 0, 0
+	}
+
+	staleMutex.RLock()
+	whileRevalidate, ifError = staleWhileRevalidate, staleIfError
+	staleMutex.RUnlock()
+
+	if seconds, ok := cc.Seconds("stale-while-revalidate"); ok {
+		whileRevalidate = seconds
+	}
+	if seconds, ok := cc.Seconds("stale-if-error"); ok {
+		ifError = seconds
+	}
+	return
+}
+
+// staleUntil returns the latest time the cache could be served
+// stale, or zero time if it could not be served stale
+func staleUntil(cache *Cache) time.Time {
+	expires, ok := freshUntil(cache)
+	if !ok {
+		return time.Time{}
+	}
+	whileRevalidate, ifError := staleWindows(cache)
+	if whileRevalidate == 0 && ifError == 0 {
+		return time.Time{}
+	}
+	if whileRevalidate > ifError {
+		return expires.Add(whileRevalidate)
+	}
+	return expires.Add(ifError)
+}
+
+// canServeStale reports if the expired cache is still within the stale
+// window of the directive ("stale-while-revalidate" or "stale-if-error")
+func canServeStale(cache *Cache, directive string) bool {
+	if cache == nil {
+		return false
+	}
+	expires, ok := freshUntil(cache)
+	if !ok {
+		return false
+	}
+	whileRevalidate, ifError := staleWindows(cache)
+	window := whileRevalidate
+	if directive == "stale-if-error" {
+		window = ifError
+	}
+	return window > 0 && time.Now().Before(expires.Add(window))
+}
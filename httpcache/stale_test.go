@@ -0,0 +1,141 @@
+package httpcache_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yookoala/weatherhk/httpcache"
+)
+
+// saveExpired saves an expired cache of the request
+func saveExpired(t *testing.T, r *http.Request, cacheControl string) {
+	cache := httpcache.NewCache(httptest.NewRecorder())
+	cache.Header().Set("Expires", rfc2616(time.Now().Add(-10*time.Second)))
+	if cacheControl != "" {
+		cache.Header().Set("Cache-Control", cacheControl)
+	}
+	fmt.Fprint(cache, "Stale message")
+	if err := httpcache.Save(r, cache); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestCacheHandler_staleWhileRevalidate(t *testing.T) {
+	var called int32
+	handler := httpcache.CacheHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&called, 1)
+		w.Header().Set("Expires", rfc2616(time.Now().Add(60*time.Second)))
+		fmt.Fprint(w, "Fresh message")
+	}))
+	r, _ := http.NewRequest("GET", "/stale-while-revalidate.html", nil)
+	defer httpcache.Delete(r)
+	saveExpired(t, r, "public, max-age=0, stale-while-revalidate=60")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if want, have := "Stale message", w.Body.String(); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := `110 - "Response is Stale"`, w.Header().Get("Warning"); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	// wait for the background refresh
+	time.Sleep(100 * time.Millisecond)
+	if want, have := int32(1), atomic.LoadInt32(&called); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if want, have := "Fresh message", w.Body.String(); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := "", w.Header().Get("Warning"); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
+
+func TestCacheHandler_staleIfError(t *testing.T) {
+	handler := httpcache.CacheHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprint(w, "Error message")
+	}))
+	r, _ := http.NewRequest("GET", "/stale-if-error.html", nil)
+	defer httpcache.Delete(r)
+	saveExpired(t, r, "public, max-age=0, stale-if-error=60")
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if want, have := http.StatusOK, w.Code; want != have {
+			t.Errorf("expected %#v, got %#v", want, have)
+		}
+		if want, have := "Stale message", w.Body.String(); want != have {
+			t.Errorf("expected %#v, got %#v", want, have)
+		}
+		if want, have := `111 - "Revalidation Failed"`, w.Header().Get("Warning"); want != have {
+			t.Errorf("expected %#v, got %#v", want, have)
+		}
+
+		// failed response is not cached
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestCacheHandler_staleIfErrorPanic(t *testing.T) {
+	handler := httpcache.CacheHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("upstream is down")
+	}))
+	r, _ := http.NewRequest("GET", "/stale-if-error-panic.html", nil)
+	defer httpcache.Delete(r)
+	saveExpired(t, r, "stale-if-error=60")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if want, have := "Stale message", w.Body.String(); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
+
+func TestCacheHandler_staleWindowExpired(t *testing.T) {
+	handler := httpcache.CacheHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprint(w, "Error message")
+	}))
+	r, _ := http.NewRequest("GET", "/stale-window-expired.html", nil)
+	defer httpcache.Delete(r)
+
+	// expired 10 seconds ago, beyond the stale window
+	saveExpired(t, r, "stale-if-error=5")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if want, have := http.StatusBadGateway, w.Code; want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if want, have := "Error message", w.Body.String(); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
+
+func TestSetStaleWindows(t *testing.T) {
+	httpcache.SetStaleWindows(0, time.Minute)
+	defer httpcache.SetStaleWindows(0, 0)
+
+	handler := httpcache.CacheHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	r, _ := http.NewRequest("GET", "/stale-default.html", nil)
+	defer httpcache.Delete(r)
+
+	// without directive, the default window applies
+	saveExpired(t, r, "")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if want, have := "Stale message", w.Body.String(); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
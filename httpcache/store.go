@@ -0,0 +1,148 @@
+package httpcache
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Store stores encoded cache of responses by key
+type Store interface {
+
+	// Get returns the value stored with the key. If there is no
+	// unexpired value of the key, it returns CacheMiss error.
+	Get(key string) ([]byte, error)
+
+	// Set stores the value with the key for the expiration duration
+	Set(key string, value []byte, expiration time.Duration) error
+
+	// Delete removes the value stored with the key, if any
+	Delete(key string) error
+}
+
+// defaultMemoryStoreSize is the default maximum number of responses
+// cached in MemoryStore
+const defaultMemoryStoreSize = 1000
+
+// defaults of the near store in TieredStore
+const defaultNearSize = 100
+const defaultNearTTL = time.Minute
+
+// invalidationChannel is the redis pub/sub channel for invalidation of
+// near cache
+const invalidationChannel = "httpcache:invalidate"
+
+var store Store
+var storeMutex sync.RWMutex
+
+func init() {
+	s, err := storeFromEnv()
+	if err != nil {
+		log.Printf("httpcache disabled: %s", err.Error())
+		return
+	}
+	store = s
+	locker = lockerOf(s)
+}
+
+// storeFromEnv creates Store by environment variables:
+//
+//	HTTPCACHE_STORE       "tiered", "redis", "memory", "file" or "none".
+//	                      Defaults to "tiered" if REDIS_URL is set, or
+//	                      "memory".
+//	REDIS_URL             URL of redis server for "tiered" and "redis"
+//	                      store
+//	HTTPCACHE_NEAR_SIZE   maximum number of responses in the near cache
+//	                      of "tiered" store
+//	HTTPCACHE_NEAR_TTL    maximum time to keep responses in the near cache
+//	                      of "tiered" store (e.g. "30s")
+//	HTTPCACHE_DIR         directory for "file" store. Defaults to
+//	                      "weatherhk-httpcache" in the temp directory.
+//	HTTPCACHE_MEMORY_SIZE maximum number of responses in "memory" store
+func storeFromEnv() (Store, error) {
+	kind := os.Getenv("HTTPCACHE_STORE")
+	if kind == "" {
+		kind = "memory"
+		if os.Getenv("REDIS_URL") != "" {
+			kind = "tiered"
+		}
+	}
+
+	switch kind {
+	case "tiered":
+		far, err := NewRedisStore(os.Getenv("REDIS_URL"))
+		if err != nil {
+			return nil, err
+		}
+		size, err := envSize("HTTPCACHE_NEAR_SIZE", defaultNearSize)
+		if err != nil {
+			return nil, err
+		}
+		ttl := defaultNearTTL
+		if ttlStr := os.Getenv("HTTPCACHE_NEAR_TTL"); ttlStr != "" {
+			if ttl, err = time.ParseDuration(ttlStr); err != nil || ttl <= 0 {
+				return nil, fmt.Errorf("invalid HTTPCACHE_NEAR_TTL: %#v", ttlStr)
+			}
+		}
+		return NewTieredStore(NewMemoryStore(size), far, ttl,
+			NewRedisInvalidator(far, invalidationChannel)), nil
+	case "redis":
+		s, err := NewRedisStore(os.Getenv("REDIS_URL"))
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	case "memory":
+		size, err := envSize("HTTPCACHE_MEMORY_SIZE", defaultMemoryStoreSize)
+		if err != nil {
+			return nil, err
+		}
+		return NewMemoryStore(size), nil
+	case "file":
+		dir := os.Getenv("HTTPCACHE_DIR")
+		if dir == "" {
+			dir = filepath.Join(os.TempDir(), "weatherhk-httpcache")
+		}
+		s, err := NewFileStore(dir)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	case "none":
+		return nil, fmt.Errorf("HTTPCACHE_STORE is none")
+	}
+	return nil, fmt.Errorf("unknown HTTPCACHE_STORE: %#v", kind)
+}
+
+// envSize reads a positive size from the environment variable, or returns
+// the default value if it is not set
+func envSize(name string, defaultSize int) (int, error) {
+	sizeStr := os.Getenv(name)
+	if sizeStr == "" {
+		return defaultSize, nil
+	}
+	size, err := strconv.Atoi(sizeStr)
+	if err != nil || size <= 0 {
+		return 0, fmt.Errorf("invalid %s: %#v", name, sizeStr)
+	}
+	return size, nil
+}
+
+// SetStore sets the Store to cache responses in. Caching is disabled
+// if the store is nil. The Locker is not changed (see SetLocker).
+func SetStore(s Store) {
+	storeMutex.Lock()
+	defer storeMutex.Unlock()
+	store = s
+}
+
+// currentStore returns the Store to cache responses in, or nil
+func currentStore() Store {
+	storeMutex.RLock()
+	defer storeMutex.RUnlock()
+	return store
+}
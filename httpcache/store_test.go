@@ -0,0 +1,150 @@
+package httpcache_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yookoala/weatherhk/httpcache"
+)
+
+func testStore(t *testing.T, store httpcache.Store) {
+	if _, err := store.Get("page://missing"); err != httpcache.CacheMiss {
+		t.Errorf("expected %#v, got %#v", httpcache.CacheMiss, err)
+	}
+
+	if err := store.Set("page://key?a=b", []byte("hello"), time.Minute); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	b, err := store.Get("page://key?a=b")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want, have := "hello", string(b); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	// overwrite
+	if err := store.Set("page://key?a=b", []byte("world"), time.Minute); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if b, _ := store.Get("page://key?a=b"); string(b) != "world" {
+		t.Errorf("expected %#v, got %#v", "world", string(b))
+	}
+
+	// delete, also for missing key
+	if err := store.Delete("page://key?a=b"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if _, err := store.Get("page://key?a=b"); err != httpcache.CacheMiss {
+		t.Errorf("expected %#v, got %#v", httpcache.CacheMiss, err)
+	}
+	if err := store.Delete("page://key?a=b"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	// expiration
+	if err := store.Set("page://expiring", []byte("hello"), 10*time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, err := store.Get("page://expiring"); err != httpcache.CacheMiss {
+		t.Errorf("expected %#v, got %#v", httpcache.CacheMiss, err)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, httpcache.NewMemoryStore(10))
+}
+
+func TestMemoryStore_evict(t *testing.T) {
+	store := httpcache.NewMemoryStore(2)
+	store.Set("a", []byte("a"), time.Minute)
+	store.Set("b", []byte("b"), time.Minute)
+	store.Get("a") // "b" becomes the least recently used
+	store.Set("c", []byte("c"), time.Minute)
+
+	if want, have := 2, store.Len(); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+	if _, err := store.Get("b"); err != httpcache.CacheMiss {
+		t.Errorf("expected %#v, got %#v", httpcache.CacheMiss, err)
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, err := store.Get(key); err != nil {
+			t.Errorf("unexpected error for %#v: %s", key, err)
+		}
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "httpcache-test-")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := httpcache.NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	testStore(t, store)
+
+	// entries survive across instances of the same directory
+	store.Set("page://persist", []byte("hello"), time.Minute)
+	another, _ := httpcache.NewFileStore(dir)
+	if b, err := another.Get("page://persist"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	} else if want, have := "hello", string(b); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+}
+
+func TestFileStore_Sweep(t *testing.T) {
+	dir, err := ioutil.TempDir("", "httpcache-test-")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := httpcache.NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	store.Set("page://expired", []byte("old"), -time.Second)
+	store.Set("page://fresh", []byte("new"), time.Minute)
+	if err := ioutil.WriteFile(filepath.Join(dir, "README"), []byte("not cache"), 0644); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := store.Sweep(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want, have := 2, len(infos); want != have {
+		t.Errorf("expected %#v files, got %#v", want, have)
+	}
+	if _, err := store.Get("page://fresh"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "README")); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestRedisStore(t *testing.T) {
+	url := os.Getenv("REDIS_URL")
+	if url == "" {
+		t.Skip("REDIS_URL not set, test skipped")
+	}
+	store, err := httpcache.NewRedisStore(url)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	testStore(t, store)
+}
@@ -0,0 +1,259 @@
+package httpcache
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// ttlStore is a Store which can report the time to live of entries
+type ttlStore interface {
+	Store
+
+	// GetTTL returns the value stored with the key and its remaining
+	// time to live. If there is no unexpired value of the key, it returns
+	// CacheMiss error.
+	GetTTL(key string) ([]byte, time.Duration, error)
+}
+
+// Invalidator broadcasts invalidation of cache entries among instances
+// of the application sharing a Store
+type Invalidator interface {
+
+	// Invalidate broadcasts the invalidation of the key to other instances
+	Invalidate(key string) error
+
+	// Listen subscribes to invalidations, calls ready once subscribed,
+	// then calls fn with keys invalidated by other instances until the
+	// Invalidator is closed or the subscription fails
+	Listen(ready func(), fn func(key string)) error
+
+	// Close stops listening to invalidations
+	Close() error
+}
+
+// TieredStore keeps recently used entries of a shared far Store (e.g.
+// RedisStore) in a near MemoryStore, so hot entries are served without
+// round-trip to the far store.
+//
+// Entries are kept in the near store no longer than their remaining time
+// in the far store (if it can report so) or the near TTL. Entries set or
+// deleted are invalidated in near stores of other instances through the
+// Invalidator, if any. The near store is bypassed while not listening to
+// invalidations (e.g. redis is unavailable), as they would be missed.
+// Values read from the far store are not kept in the near store if the
+// key is invalidated during the read, as the value might be outdated.
+type TieredStore struct {
+	near        *MemoryStore
+	far         Store
+	nearTTL     time.Duration
+	invalidator Invalidator
+
+	mutex     sync.Mutex
+	listening bool
+	closed    bool
+
+	fillMutex sync.Mutex
+	fills     map[string]*nearFill
+}
+
+// nearFill tracks the reads of a key from the far store in flight. The
+// generation is bumped whenever the key is invalidated, so a read can
+// tell if its value is still current before filling the near store.
+type nearFill struct {
+	generation uint64
+	readers    int
+}
+
+// minListenBackoff and maxListenBackoff bound the wait before retrying
+// to listen to invalidations
+const minListenBackoff = 100 * time.Millisecond
+const maxListenBackoff = 30 * time.Second
+
+// NewTieredStore creates a TieredStore of a near and a far store. The
+// invalidator can be nil for single instance deployments.
+func NewTieredStore(near *MemoryStore, far Store, nearTTL time.Duration, invalidator Invalidator) *TieredStore {
+	s := &TieredStore{
+		near:        near,
+		far:         far,
+		nearTTL:     nearTTL,
+		invalidator: invalidator,
+		fills:       make(map[string]*nearFill),
+	}
+	if invalidator != nil {
+		go s.listen()
+	}
+	return s
+}
+
+// listen listens to invalidations from other instances until closed,
+// retrying with exponential backoff if the subscription fails
+func (s *TieredStore) listen() {
+	backoff := minListenBackoff
+	ready := func() {
+		s.mutex.Lock()
+		s.listening = true
+		s.mutex.Unlock()
+		backoff = minListenBackoff
+	}
+	for {
+		err := s.invalidator.Listen(ready, s.invalidate)
+
+		// entries might have been invalidated since the subscription failed
+		s.mutex.Lock()
+		s.listening = false
+		closed := s.closed
+		s.mutex.Unlock()
+		s.near.purge()
+		if closed {
+			return
+		}
+
+		if err == nil {
+			log.Printf("httpcache: stopped listening to invalidation, retry in %s", backoff)
+		} else {
+			log.Printf("httpcache: stopped listening to invalidation, retry in %s: %s", backoff, err.Error())
+		}
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > maxListenBackoff {
+			backoff = maxListenBackoff
+		}
+	}
+}
+
+// useNear tells if the near store is in sync with other instances
+func (s *TieredStore) useNear() bool {
+	if s.invalidator == nil {
+		return true
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.listening
+}
+
+// nearExpiration returns the time an entry of the far store could be
+// kept in the near store
+func (s *TieredStore) nearExpiration(expiration time.Duration) time.Duration {
+	if expiration <= 0 || expiration > s.nearTTL {
+		return s.nearTTL
+	}
+	return expiration
+}
+
+// Get implements Store
+func (s *TieredStore) Get(key string) ([]byte, error) {
+	if !s.useNear() {
+		return s.far.Get(key)
+	}
+	if b, err := s.near.Get(key); err == nil {
+		return b, nil
+	}
+
+	generation := s.startFill(key)
+	var b []byte
+	var err error
+	expiration := s.nearTTL
+	if far, ok := s.far.(ttlStore); ok {
+		b, expiration, err = far.GetTTL(key)
+	} else {
+		b, err = s.far.Get(key)
+	}
+	if err != nil {
+		s.endFill(key, generation, nil, 0)
+		return nil, err
+	}
+	s.endFill(key, generation, b, s.nearExpiration(expiration))
+	return b, nil
+}
+
+// startFill registers a read of the key from the far store and returns
+// the current generation of the key
+func (s *TieredStore) startFill(key string) uint64 {
+	s.fillMutex.Lock()
+	defer s.fillMutex.Unlock()
+	fill, ok := s.fills[key]
+	if !ok {
+		fill = &nearFill{}
+		s.fills[key] = fill
+	}
+	fill.readers++
+	return fill.generation
+}
+
+// endFill ends a read of the key from the far store. The value read, if
+// not nil, is kept in the near store unless the key has been invalidated
+// since the read started.
+func (s *TieredStore) endFill(key string, generation uint64, value []byte, expiration time.Duration) {
+	s.fillMutex.Lock()
+	defer s.fillMutex.Unlock()
+	fill := s.fills[key]
+	if value != nil && fill.generation == generation {
+		s.near.Set(key, value, expiration)
+	}
+	if fill.readers--; fill.readers == 0 {
+		delete(s.fills, key)
+	}
+}
+
+// bump invalidates reads of the key from the far store in flight, so
+// their values would not be kept in the near store
+func (s *TieredStore) bump(key string) {
+	s.fillMutex.Lock()
+	defer s.fillMutex.Unlock()
+	if fill, ok := s.fills[key]; ok {
+		fill.generation++
+	}
+}
+
+// Set implements Store
+func (s *TieredStore) Set(key string, value []byte, expiration time.Duration) error {
+	if err := s.far.Set(key, value, expiration); err != nil {
+		s.near.Delete(key)
+		return err
+	}
+	s.bump(key)
+	if s.useNear() {
+		s.near.Set(key, value, s.nearExpiration(expiration))
+	} else {
+		s.near.Delete(key)
+	}
+	return s.broadcast(key)
+}
+
+// Delete implements Store
+func (s *TieredStore) Delete(key string) error {
+	s.near.Delete(key)
+	if err := s.far.Delete(key); err != nil {
+		return err
+	}
+
+	// remove the value filled by reads in flight before the bump
+	s.bump(key)
+	s.near.Delete(key)
+	return s.broadcast(key)
+}
+
+// Close stops listening to invalidations from other instances
+func (s *TieredStore) Close() error {
+	if s.invalidator == nil {
+		return nil
+	}
+	s.mutex.Lock()
+	s.closed = true
+	s.mutex.Unlock()
+	return s.invalidator.Close()
+}
+
+// broadcast invalidates the key in near stores of other instances
+func (s *TieredStore) broadcast(key string) error {
+	if s.invalidator == nil {
+		return nil
+	}
+	return s.invalidator.Invalidate(key)
+}
+
+// invalidate removes the key invalidated by other instances
+func (s *TieredStore) invalidate(key string) {
+	s.bump(key)
+	s.near.Delete(key)
+}
@@ -0,0 +1,249 @@
+package httpcache_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yookoala/weatherhk/httpcache"
+)
+
+// testBus connects testInvalidators like a pub/sub channel
+type testBus struct {
+	mutex     sync.Mutex
+	listeners map[*testInvalidator]func(key string)
+}
+
+func (bus *testBus) invalidator() *testInvalidator {
+	return &testInvalidator{
+		bus:    bus,
+		closed: make(chan bool),
+		ready:  make(chan bool),
+	}
+}
+
+type testInvalidator struct {
+	bus    *testBus
+	closed chan bool
+	ready  chan bool
+}
+
+func (inv *testInvalidator) Invalidate(key string) error {
+	inv.bus.mutex.Lock()
+	defer inv.bus.mutex.Unlock()
+	for other, fn := range inv.bus.listeners {
+		if other != inv {
+			fn(key)
+		}
+	}
+	return nil
+}
+
+func (inv *testInvalidator) Listen(ready func(), fn func(key string)) error {
+	inv.bus.mutex.Lock()
+	if inv.bus.listeners == nil {
+		inv.bus.listeners = make(map[*testInvalidator]func(key string))
+	}
+	inv.bus.listeners[inv] = fn
+	inv.bus.mutex.Unlock()
+	ready()
+	close(inv.ready)
+
+	<-inv.closed
+	return nil
+}
+
+func (inv *testInvalidator) Close() error {
+	close(inv.closed)
+	return nil
+}
+
+// failingInvalidator fails to subscribe until recovered
+type failingInvalidator struct {
+	*testInvalidator
+	mutex   sync.Mutex
+	failing bool
+}
+
+func (inv *failingInvalidator) Listen(ready func(), fn func(key string)) error {
+	inv.mutex.Lock()
+	failing := inv.failing
+	inv.mutex.Unlock()
+	if failing {
+		return errors.New("connection refused")
+	}
+	return inv.testInvalidator.Listen(ready, fn)
+}
+
+func (inv *failingInvalidator) recover() {
+	inv.mutex.Lock()
+	defer inv.mutex.Unlock()
+	inv.failing = false
+}
+
+// pausingStore pauses GetTTL after reading the far store, until resumed
+type pausingStore struct {
+	*httpcache.MemoryStore
+	read   chan bool
+	resume chan bool
+}
+
+func (s *pausingStore) GetTTL(key string) ([]byte, time.Duration, error) {
+	b, ttl, err := s.MemoryStore.GetTTL(key)
+	s.read <- true
+	<-s.resume
+	return b, ttl, err
+}
+
+func TestTieredStore(t *testing.T) {
+	testStore(t, httpcache.NewTieredStore(httpcache.NewMemoryStore(10), httpcache.NewMemoryStore(10), time.Minute, nil))
+}
+
+func TestTieredStore_near(t *testing.T) {
+	far := httpcache.NewMemoryStore(10)
+	store := httpcache.NewTieredStore(httpcache.NewMemoryStore(10), far, time.Minute, nil)
+
+	// entry served from near store without reaching far store
+	store.Set("hot", []byte("hello"), time.Minute)
+	far.Delete("hot")
+	if b, err := store.Get("hot"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	} else if want, have := "hello", string(b); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	// delete removes entry from both stores
+	far.Set("hot", []byte("hello"), time.Minute)
+	store.Delete("hot")
+	if _, err := store.Get("hot"); err != httpcache.CacheMiss {
+		t.Errorf("expected %#v, got %#v", httpcache.CacheMiss, err)
+	}
+}
+
+func TestTieredStore_ttl(t *testing.T) {
+	far := httpcache.NewMemoryStore(10)
+	store := httpcache.NewTieredStore(httpcache.NewMemoryStore(10), far, time.Minute, nil)
+
+	// entry loaded from far store expires with it in near store
+	far.Set("expiring", []byte("hello"), 20*time.Millisecond)
+	if _, err := store.Get("expiring"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if _, err := store.Get("expiring"); err != httpcache.CacheMiss {
+		t.Errorf("expected %#v, got %#v", httpcache.CacheMiss, err)
+	}
+
+	// entry kept in near store no longer than the near TTL
+	store = httpcache.NewTieredStore(httpcache.NewMemoryStore(10), far, 20*time.Millisecond, nil)
+	store.Set("hot", []byte("hello"), time.Minute)
+	far.Set("hot", []byte("updated"), time.Minute)
+	time.Sleep(30 * time.Millisecond)
+	if b, _ := store.Get("hot"); string(b) != "updated" {
+		t.Errorf("expected %#v, got %#v", "updated", string(b))
+	}
+}
+
+func TestTieredStore_invalidation(t *testing.T) {
+	far := httpcache.NewMemoryStore(10)
+	bus := &testBus{}
+	inv1, inv2 := bus.invalidator(), bus.invalidator()
+	store1 := httpcache.NewTieredStore(httpcache.NewMemoryStore(10), far, time.Minute, inv1)
+	store2 := httpcache.NewTieredStore(httpcache.NewMemoryStore(10), far, time.Minute, inv2)
+	defer store1.Close()
+	defer store2.Close()
+	<-inv1.ready
+	<-inv2.ready
+
+	store1.Set("key", []byte("v1"), time.Minute)
+	if b, _ := store2.Get("key"); string(b) != "v1" {
+		t.Fatalf("expected %#v, got %#v", "v1", string(b))
+	}
+
+	// update by another instance
+	store1.Set("key", []byte("v2"), time.Minute)
+	if b, _ := store2.Get("key"); string(b) != "v2" {
+		t.Errorf("expected %#v, got %#v", "v2", string(b))
+	}
+
+	// delete by another instance
+	store1.Delete("key")
+	if _, err := store2.Get("key"); err != httpcache.CacheMiss {
+		t.Errorf("expected %#v, got %#v", httpcache.CacheMiss, err)
+	}
+}
+
+func TestTieredStore_listenRetry(t *testing.T) {
+	far := httpcache.NewMemoryStore(10)
+	inv := &failingInvalidator{
+		testInvalidator: (&testBus{}).invalidator(),
+		failing:         true,
+	}
+	store := httpcache.NewTieredStore(httpcache.NewMemoryStore(10), far, time.Minute, inv)
+	defer store.Close()
+
+	// near store is bypassed while not listening to invalidations
+	store.Set("key", []byte("v1"), time.Minute)
+	far.Set("key", []byte("v2"), time.Minute)
+	if b, _ := store.Get("key"); string(b) != "v2" {
+		t.Errorf("expected %#v, got %#v", "v2", string(b))
+	}
+
+	// near store is used again once listening
+	inv.recover()
+	select {
+	case <-inv.ready:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected listening to be retried")
+	}
+	if b, _ := store.Get("key"); string(b) != "v2" {
+		t.Errorf("expected %#v, got %#v", "v2", string(b))
+	}
+	far.Set("key", []byte("v3"), time.Minute)
+	if b, _ := store.Get("key"); string(b) != "v2" {
+		t.Errorf("expected near entry %#v, got %#v", "v2", string(b))
+	}
+}
+
+func TestTieredStore_invalidationDuringRead(t *testing.T) {
+	far := httpcache.NewMemoryStore(10)
+	paused := &pausingStore{
+		MemoryStore: far,
+		read:        make(chan bool),
+		resume:      make(chan bool),
+	}
+	bus := &testBus{}
+	inv1, inv2 := bus.invalidator(), bus.invalidator()
+	store1 := httpcache.NewTieredStore(httpcache.NewMemoryStore(10), paused, time.Minute, inv1)
+	store2 := httpcache.NewTieredStore(httpcache.NewMemoryStore(10), far, time.Minute, inv2)
+	defer store1.Close()
+	defer store2.Close()
+	<-inv1.ready
+	<-inv2.ready
+
+	// another instance updates the key after the old value is read from
+	// the far store, but before it is kept in the near store
+	far.Set("key", []byte("v1"), time.Minute)
+	done := make(chan []byte)
+	go func() {
+		b, _ := store1.Get("key")
+		done <- b
+	}()
+	<-paused.read
+	store2.Set("key", []byte("v2"), time.Minute)
+	close(paused.resume)
+	if want, have := "v1", string(<-done); want != have {
+		t.Errorf("expected %#v, got %#v", want, have)
+	}
+
+	// the outdated value is not kept in the near store
+	go func() {
+		for range paused.read {
+		}
+	}()
+	defer close(paused.read)
+	if b, _ := store1.Get("key"); string(b) != "v2" {
+		t.Errorf("expected %#v, got %#v", "v2", string(b))
+	}
+}